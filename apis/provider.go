@@ -253,6 +253,11 @@ func execute(
 	g := got.New()
 	g.Retries = 5
 	g.MaxSize = 0
+	// send the request with the shared client, so that options such as
+	// --trace apply, keeping the timeout of go-got's default client
+	httpClient := *client.HTTPClient
+	httpClient.Timeout = got.DefaultClient.Timeout
+	g.Client = &httpClient
 
 	req := g.NewRequest(method, url, input)
 
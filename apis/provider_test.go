@@ -6,10 +6,14 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/spf13/pflag"
 	assert "github.com/stretchr/testify/require"
 	"github.com/taskcluster/taskcluster-cli/apis/definitions"
+	"github.com/taskcluster/taskcluster-cli/client"
 	"github.com/taskcluster/taskcluster-cli/config"
 )
 
@@ -57,6 +61,68 @@ func TestCommandGeneration(t *testing.T) {
 	assert.Equal(expected, actual, "request sent to test server was invalid, replied: %s", actual)
 }
 
+// TestCommandTrace checks that the generated commands send their requests
+// with the shared client, so that --trace applies to them
+func TestCommandTrace(t *testing.T) {
+	assert := assert.New(t)
+
+	providerServer := apiServer()
+	defer providerServer.Close()
+
+	def := servicesTest["Test"]
+	def.BaseURL = providerServer.URL
+	servicesTest["Test"] = def
+	cmd := makeCmdFromDefinition("Test", servicesTest["Test"])
+	subCmd, _, err := cmd.Find([]string{"test", "test"})
+	assert.NoError(err)
+	subCmd.SetOutput(&bytes.Buffer{})
+	config.Setup()
+
+	defer func(transport http.RoundTripper) { client.HTTPClient.Transport = transport }(client.HTTPClient.Transport)
+	trace := &bytes.Buffer{}
+	client.EnableTrace(trace)
+
+	cmd.SetArgs([]string{"test", "test"})
+	assert.NoError(cmd.Execute())
+	assert.Contains(trace.String(), "> GET "+providerServer.URL+"/test HTTP/1.1\n")
+	assert.Contains(trace.String(), "< HTTP/1.1 200 OK\n")
+}
+
+// TestCommandTransport checks that the transport flags apply to api
+// commands: the first request outlasts --response-header-timeout, so it is
+// retried.
+func TestCommandTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	var requests int32
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		apiHandler(w, r)
+	}))
+	defer slowServer.Close()
+
+	def := servicesTest["Test"]
+	def.BaseURL = slowServer.URL
+	servicesTest["Test"] = def
+	cmd := makeCmdFromDefinition("Test", servicesTest["Test"])
+	buf := &bytes.Buffer{}
+	cmd.SetOutput(buf)
+	config.Setup()
+
+	defer func(transport http.RoundTripper) { client.HTTPClient.Transport = transport }(client.HTTPClient.Transport)
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	client.AddTransportFlags(flags)
+	assert.NoError(flags.Set("response-header-timeout", "50ms"))
+	assert.NoError(client.ConfigureTransport(flags))
+
+	cmd.SetArgs([]string{"test", "test"})
+	assert.NoError(cmd.Execute())
+	assert.Equal(int32(2), atomic.LoadInt32(&requests))
+	assert.Equal("true", buf.String())
+}
+
 // the code from which we generate the test command
 var servicesTest = map[string]definitions.Service{
 	"Test": definitions.Service{
@@ -0,0 +1,9 @@
+package apis
+
+// Schema returns the JSON schema with the given id, e.g.
+// "http://schemas.taskcluster.net/queue/v1/create-task-request.json#", from
+// the schemas bundled with the API definitions.
+func Schema(id string) (schema string, ok bool) {
+	schema, ok = schemas[id]
+	return
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/pflag"
+)
+
+// AddColorFlag registers the --color flag on flags, which should be the
+// persistent flags of the root command.
+func AddColorFlag(flags *pflag.FlagSet) {
+	flags.String("color", "auto", "When to color the output: auto, when stdout is a terminal, always or never.")
+}
+
+// ConfigureColor sets color.NoColor from the --color flag of flags.
+func ConfigureColor(flags *pflag.FlagSet) error {
+	when, err := flags.GetString("color")
+	if err != nil {
+		return err
+	}
+	switch when {
+	case "auto":
+		fd := os.Stdout.Fd()
+		color.NoColor = os.Getenv("TERM") == "dumb" || !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd)
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	default:
+		return fmt.Errorf("invalid --color '%s', expected one of auto, always, never", when)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/spf13/pflag"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestConfigureColor(t *testing.T) {
+	assert := assert.New(t)
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+
+	flags := func(args ...string) *pflag.FlagSet {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		AddColorFlag(fs)
+		assert.NoError(fs.Parse(args))
+		return fs
+	}
+
+	assert.NoError(ConfigureColor(flags("--color", "always")))
+	assert.False(color.NoColor)
+	assert.NoError(ConfigureColor(flags("--color", "never")))
+	assert.True(color.NoColor)
+	assert.NoError(ConfigureColor(flags()))
+	assert.Error(ConfigureColor(flags("--color", "sometimes")))
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+)
+
+// InterruptContext returns a context derived from parent which is cancelled
+// on Ctrl-C, and a function to call once it is no longer needed.
+func InterruptContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(interrupt)
+		cancel()
+	}
+}
+
+// ContextClient is an HTTP client sending every request with Context, for
+// the taskcluster service clients, whose calls don't take a context.
+type ContextClient struct {
+	Context context.Context
+	Client  *http.Client
+}
+
+// Do sends req with the context of c.
+func (c *ContextClient) Do(req *http.Request) (*http.Response, error) {
+	return c.Client.Do(req.WithContext(c.Context))
+}
+
+// CallWithContext runs call, which should make its requests with a
+// ContextClient of ctx, and returns its error, or the error of ctx if it is
+// done first. The latter doesn't wait for call to return, as the service
+// clients keep retrying failed requests for a while.
+func CallWithContext(ctx context.Context, call func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
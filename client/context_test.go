@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestContextClient(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &ContextClient{Context: ctx, Client: &http.Client{}}
+	req, err := http.NewRequest("GET", server.URL, nil)
+	assert.NoError(err)
+
+	resp, err := c.Do(req)
+	assert.NoError(err)
+	resp.Body.Close()
+
+	cancel()
+	_, err = c.Do(req)
+	assert.Error(err, "requests should fail once the context is cancelled")
+}
+
+func TestCallWithContext(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("boom", CallWithContext(context.Background(), func() error {
+		return errors.New("boom")
+	}).Error())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+	assert.Equal(context.DeadlineExceeded, CallWithContext(ctx, func() error {
+		<-release
+		return nil
+	}))
+}
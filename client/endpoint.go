@@ -0,0 +1,33 @@
+package client
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// AddEndpointFlag registers the --endpoint flag on flags, which should be the
+// persistent flags of a service subtree, so that every command of the subtree
+// can be pointed at another instance of service, such as a mock server.
+func AddEndpointFlag(flags *pflag.FlagSet, service string) {
+	flags.String("endpoint", "", "Base URL of the "+service+" service, overriding the default; "+
+		"defaults to the "+EndpointEnvVar(service)+" environment variable.")
+}
+
+// EndpointEnvVar returns the name of the environment variable that overrides
+// the base URL of service, e.g. TASKCLUSTER_QUEUE_ENDPOINT.
+func EndpointEnvVar(service string) string {
+	return "TASKCLUSTER_" + strings.ToUpper(strings.Replace(service, "-", "_", -1)) + "_ENDPOINT"
+}
+
+// Endpoint returns the base URL override for service: the value of the
+// --endpoint flag if it is set, otherwise that of the environment variable
+// named by EndpointEnvVar. An empty string means the client's default base
+// URL should be used.
+func Endpoint(flags *pflag.FlagSet, service string) string {
+	if endpoint, err := flags.GetString("endpoint"); err == nil && endpoint != "" {
+		return strings.TrimRight(endpoint, "/")
+	}
+	return strings.TrimRight(os.Getenv(EndpointEnvVar(service)), "/")
+}
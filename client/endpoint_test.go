@@ -0,0 +1,43 @@
+package client
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestEndpoint(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.Unsetenv("TASKCLUSTER_QUEUE_ENDPOINT")
+	os.Unsetenv("TASKCLUSTER_QUEUE_ENDPOINT")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddEndpointFlag(flags, "queue")
+	assert.Equal("", Endpoint(flags, "queue"), "no override should be empty")
+
+	os.Setenv("TASKCLUSTER_QUEUE_ENDPOINT", "http://localhost:8080/env/")
+	assert.Equal("http://localhost:8080/env", Endpoint(flags, "queue"))
+
+	assert.NoError(flags.Set("endpoint", "http://localhost:8080/flag"))
+	assert.Equal("http://localhost:8080/flag", Endpoint(flags, "queue"), "the flag should take precedence")
+}
+
+func TestEndpointWithoutFlag(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.Unsetenv("TASKCLUSTER_HOOKS_ENDPOINT")
+	os.Setenv("TASKCLUSTER_HOOKS_ENDPOINT", "http://localhost:8080")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	assert.Equal("http://localhost:8080", Endpoint(flags, "hooks"))
+}
+
+func TestEndpointEnvVar(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("TASKCLUSTER_AUTH_ENDPOINT", EndpointEnvVar("auth"))
+	assert.Equal("TASKCLUSTER_PURGE_CACHE_ENDPOINT", EndpointEnvVar("purge-cache"))
+}
@@ -0,0 +1,172 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// AddFieldsFlag registers the --fields flag on flags, for commands with a JSON
+// output, so that it can be restricted to some of the fields of the results.
+// example lists some of those fields, such as "name,state", for the help.
+func AddFieldsFlag(flags *pflag.FlagSet, example string) {
+	flags.StringSlice("fields", nil, "Only output these fields of the results in JSON, e.g. --fields "+example+".")
+}
+
+// Fields returns the fields named with the --fields flag of flags, if any.
+func Fields(flags *pflag.FlagSet) []string {
+	fields, err := flags.GetStringSlice("fields")
+	if err != nil {
+		return nil
+	}
+	return fields
+}
+
+// ValidateFields checks that the fields named with --fields are JSON fields of
+// record, a struct, or a slice of structs, or pointers to those. Commands call
+// it before doing any work, to fail early on typos.
+func ValidateFields(flags *pflag.FlagSet, record interface{}) error {
+	_, err := fieldIndexes(Fields(flags), reflect.TypeOf(record))
+	return err
+}
+
+// SelectFields returns records, a struct, or a slice of structs, or pointers
+// to those, restricted to the JSON fields named with --fields, in that order.
+// Without --fields, records is returned as is.
+func SelectFields(flags *pflag.FlagSet, records interface{}) (interface{}, error) {
+	fields := Fields(flags)
+	if len(fields) == 0 {
+		return records, nil
+	}
+	v := reflect.ValueOf(records)
+	indexes, err := fieldIndexes(fields, v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Kind() != reflect.Slice {
+		return selectRecord(v, fields, indexes), nil
+	}
+	result := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		result = append(result, selectRecord(v.Index(i), fields, indexes))
+	}
+	return result, nil
+}
+
+// PrintJSON writes records to out as JSON, see MarshalJSON, restricted to the fields
+// named with --fields, see SelectFields.
+func PrintJSON(out io.Writer, flags *pflag.FlagSet, records interface{}) error {
+	selected, err := SelectFields(flags, records)
+	if err != nil {
+		return err
+	}
+	data, err := MarshalJSON(selected)
+	if err != nil {
+		return fmt.Errorf("could not marshal the results: %v", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// selectRecord returns the fields of record at indexes, named names; a nil
+// record stays nil.
+func selectRecord(record reflect.Value, names []string, indexes []int) interface{} {
+	if record.Kind() == reflect.Ptr {
+		if record.IsNil() {
+			return nil
+		}
+		record = record.Elem()
+	}
+	selected := selectedFields{names: names}
+	for _, i := range indexes {
+		selected.values = append(selected.values, record.Field(i).Interface())
+	}
+	return selected
+}
+
+// selectedFields marshals into a JSON object of the selected fields, in the
+// order they were named, with their values even if they would be omitted
+// when empty.
+type selectedFields struct {
+	names  []string
+	values []interface{}
+}
+
+func (s selectedFields) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("{")
+	for i, name := range s.names {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, _ := json.Marshal(name)
+		value, err := json.Marshal(s.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// fieldIndexes returns the indexes, in the struct type of the records t, of
+// the fields with the given JSON names.
+func fieldIndexes(names []string, t reflect.Type) ([]int, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("--fields is not supported by this output")
+	}
+
+	known := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			known[name] = i
+		}
+	}
+	indexes := make([]int, 0, len(names))
+	for _, name := range names {
+		i, ok := known[name]
+		if !ok {
+			valid := make([]string, 0, len(known))
+			for k := range known {
+				valid = append(valid, k)
+			}
+			sort.Strings(valid)
+			return nil, fmt.Errorf("unknown field '%s' given to --fields, expected some of %s", name, strings.Join(valid, ", "))
+		}
+		indexes = append(indexes, i)
+	}
+	return indexes, nil
+}
+
+// jsonName returns the name of field in JSON, or an empty string if it isn't
+// marshalled.
+func jsonName(field reflect.StructField) string {
+	if field.PkgPath != "" {
+		// unexported
+		return ""
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return ""
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/pflag"
+	assert "github.com/stretchr/testify/require"
+)
+
+type fieldsRecord struct {
+	Service string  `json:"service"`
+	Alive   bool    `json:"alive"`
+	Latency float64 `json:"latency"`
+	Error   string  `json:"error,omitempty"`
+	Plain   int
+	Hidden  string `json:"-"`
+	private string
+}
+
+func fieldsFlags(fields string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddFieldsFlag(flags, "service,latency")
+	if fields != "" {
+		flags.Set("fields", fields)
+	}
+	return flags
+}
+
+func TestAddFieldsFlag(t *testing.T) {
+	assert := assert.New(t)
+
+	flags := fieldsFlags("")
+	assert.Equal("Only output these fields of the results in JSON, e.g. --fields service,latency.", flags.Lookup("fields").Usage)
+}
+
+func TestSelectFields(t *testing.T) {
+	assert := assert.New(t)
+
+	records := []fieldsRecord{
+		{Service: "queue", Alive: true, Latency: 0.5, Plain: 1},
+		{Service: "auth", Error: "down"},
+	}
+	selected, err := SelectFields(fieldsFlags("latency,service,error"), records)
+	assert.NoError(err)
+	data, err := json.Marshal(selected)
+	assert.NoError(err)
+	assert.Equal(`[{"latency":0.5,"service":"queue","error":""},{"latency":0,"service":"auth","error":"down"}]`, string(data),
+		"fields should be in the order given, even when omitted if empty")
+
+	selected, err = SelectFields(fieldsFlags("Plain"), &records[0])
+	assert.NoError(err)
+	data, err = json.Marshal(selected)
+	assert.NoError(err)
+	assert.Equal(`{"Plain":1}`, string(data))
+}
+
+func TestSelectFieldsUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	records := []fieldsRecord{{Service: "queue"}}
+	selected, err := SelectFields(fieldsFlags(""), records)
+	assert.NoError(err)
+	assert.Equal(records, selected, "records should be unchanged without --fields")
+}
+
+func TestValidateFields(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ValidateFields(fieldsFlags("service,alive"), []fieldsRecord(nil)))
+	for _, fields := range []string{"nope", "Hidden", "private", "Service"} {
+		assert.Error(ValidateFields(fieldsFlags(fields), fieldsRecord{}), fields)
+	}
+	assert.Error(ValidateFields(fieldsFlags("service"), []string{}), "only structs have fields")
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// HTTPClient is the HTTP client shared by all commands, both for the
+// taskcluster service clients and for plain HTTP requests, so that options
+// affecting the transport, such as --trace, apply uniformly.
+var HTTPClient = &http.Client{}
+
+// EnableTrace makes HTTPClient write every request it sends and every
+// response it receives to out, see TraceTransport.
+func EnableTrace(out io.Writer) {
+	HTTPClient.Transport = &TraceTransport{
+		Transport: HTTPClient.Transport,
+		Out:       out,
+	}
+}
+
+// TransportOptions tune the connections made by HTTPClient.
+type TransportOptions struct {
+	DialTimeout           time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	MaxIdleConns          int
+}
+
+// DefaultTransportOptions are those of http.DefaultTransport, along with a
+// timeout on response headers, which it doesn't have.
+var DefaultTransportOptions = TransportOptions{
+	DialTimeout:           30 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: time.Minute,
+	MaxIdleConns:          100,
+}
+
+// NewTransport returns a transport like http.DefaultTransport, tuned with
+// options.
+func NewTransport(options TransportOptions) *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   options.DialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout:   options.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: options.ResponseHeaderTimeout,
+		MaxIdleConns:          options.MaxIdleConns,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// AddTransportFlags registers the flags tuning the transport of HTTPClient on
+// flags, which should be the persistent flags of the root command.
+func AddTransportFlags(flags *pflag.FlagSet) {
+	flags.Duration("dial-timeout", DefaultTransportOptions.DialTimeout, "Give up on connecting to a server after this long.")
+	flags.Duration("tls-handshake-timeout", DefaultTransportOptions.TLSHandshakeTimeout, "Give up on the TLS handshake with a server after this long.")
+	flags.Duration("response-header-timeout", DefaultTransportOptions.ResponseHeaderTimeout, "Give up on a server which hasn't started to respond after this long; 0 waits forever.")
+	flags.Int("max-idle-conns", DefaultTransportOptions.MaxIdleConns, "Maximum number of idle connections kept open for reuse; 0 means no limit.")
+}
+
+// ConfigureTransport sets the transport of HTTPClient from the flags
+// registered by AddTransportFlags.
+func ConfigureTransport(flags *pflag.FlagSet) error {
+	options := DefaultTransportOptions
+	var err error
+	if options.DialTimeout, err = flags.GetDuration("dial-timeout"); err != nil {
+		return err
+	}
+	if options.TLSHandshakeTimeout, err = flags.GetDuration("tls-handshake-timeout"); err != nil {
+		return err
+	}
+	if options.ResponseHeaderTimeout, err = flags.GetDuration("response-header-timeout"); err != nil {
+		return err
+	}
+	if options.MaxIdleConns, err = flags.GetInt("max-idle-conns"); err != nil {
+		return err
+	}
+	if options.DialTimeout < 0 || options.TLSHandshakeTimeout < 0 || options.ResponseHeaderTimeout < 0 {
+		return fmt.Errorf("timeouts can't be negative")
+	}
+	if options.MaxIdleConns < 0 {
+		return fmt.Errorf("--max-idle-conns can't be negative, got %d", options.MaxIdleConns)
+	}
+	HTTPClient.Transport = NewTransport(options)
+	return nil
+}
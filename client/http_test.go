@@ -0,0 +1,31 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestConfigureTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(transport http.RoundTripper) { HTTPClient.Transport = transport }(HTTPClient.Transport)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddTransportFlags(flags)
+	assert.NoError(flags.Set("tls-handshake-timeout", "3s"))
+	assert.NoError(flags.Set("max-idle-conns", "5"))
+	assert.NoError(ConfigureTransport(flags))
+
+	transport, ok := HTTPClient.Transport.(*http.Transport)
+	assert.True(ok)
+	assert.Equal(3*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(DefaultTransportOptions.ResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+	assert.Equal(5, transport.MaxIdleConns)
+
+	assert.NoError(flags.Set("dial-timeout", "-1s"))
+	assert.Error(ConfigureTransport(flags))
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/pflag"
+)
+
+// PrettyJSON tells whether MarshalJSON indents its output, or writes it on a
+// single line; ConfigureJSON sets it from the flags registered by
+// AddJSONFlags.
+var PrettyJSON = true
+
+// AddJSONFlags registers the flags choosing how JSON output is laid out on
+// flags, which should be the persistent flags of the root command.
+func AddJSONFlags(flags *pflag.FlagSet) {
+	flags.Bool("pretty", false, "Indent JSON output; this is the default when stdout is a terminal.")
+	flags.Bool("compact", false, "Output JSON on a single line per document, for log pipelines; this is the default when stdout isn't a terminal.")
+}
+
+// ConfigureJSON sets PrettyJSON from the flags registered by AddJSONFlags,
+// defaulting to indented JSON on a terminal only.
+func ConfigureJSON(flags *pflag.FlagSet) error {
+	pretty, _ := flags.GetBool("pretty")
+	compact, _ := flags.GetBool("compact")
+	switch {
+	case pretty && compact:
+		return errors.New("--pretty and --compact can't be used together")
+	case pretty || compact:
+		PrettyJSON = pretty
+	default:
+		PrettyJSON = isatty.IsTerminal(os.Stdout.Fd())
+	}
+	return nil
+}
+
+// MarshalJSON returns the JSON encoding of v, indented if PrettyJSON is set.
+// Commands use it for their JSON output, as opposed to files they write.
+func MarshalJSON(v interface{}) ([]byte, error) {
+	if PrettyJSON {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return json.Marshal(v)
+}
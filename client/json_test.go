@@ -0,0 +1,47 @@
+package client
+
+import (
+	"os"
+	"testing"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/pflag"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+	defer func(pretty bool) { PrettyJSON = pretty }(PrettyJSON)
+
+	v := map[string][]int{"a": {1, 2}}
+
+	PrettyJSON = true
+	data, err := MarshalJSON(v)
+	assert.NoError(err)
+	assert.Equal("{\n  \"a\": [\n    1,\n    2\n  ]\n}", string(data))
+
+	PrettyJSON = false
+	data, err = MarshalJSON(v)
+	assert.NoError(err)
+	assert.Equal(`{"a":[1,2]}`, string(data))
+}
+
+func TestConfigureJSON(t *testing.T) {
+	assert := assert.New(t)
+	defer func(pretty bool) { PrettyJSON = pretty }(PrettyJSON)
+
+	flags := func(args ...string) *pflag.FlagSet {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		AddJSONFlags(fs)
+		assert.NoError(fs.Parse(args))
+		return fs
+	}
+
+	assert.NoError(ConfigureJSON(flags("--pretty")))
+	assert.True(PrettyJSON)
+	assert.NoError(ConfigureJSON(flags("--compact")))
+	assert.False(PrettyJSON)
+	assert.NoError(ConfigureJSON(flags()))
+	assert.Equal(isatty.IsTerminal(os.Stdout.Fd()), PrettyJSON)
+	assert.Error(ConfigureJSON(flags("--pretty", "--compact")))
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+)
+
+// AddCountFlag registers the --count flag on flags, for commands which list
+// results, so that they can print how many results there are instead of the
+// results themselves.
+func AddCountFlag(flags *pflag.FlagSet) {
+	flags.Bool("count", false, "Only print the number of results.")
+}
+
+// PrintCount writes n to out if the --count flag of flags is set, and reports
+// whether it did; callers should then skip printing the results themselves.
+// This lets commands with a richer output, such as JSON, honour --count before
+// rendering it.
+func PrintCount(out io.Writer, flags *pflag.FlagSet, n int) bool {
+	if count, err := flags.GetBool("count"); err != nil || !count {
+		return false
+	}
+	fmt.Fprintln(out, n)
+	return true
+}
+
+// PrintList writes items to out, one per line, or only their number if the
+// --count flag of flags is set.
+func PrintList(out io.Writer, flags *pflag.FlagSet, items []string) {
+	if PrintCount(out, flags, len(items)) {
+		return
+	}
+	for _, item := range items {
+		fmt.Fprintln(out, item)
+	}
+}
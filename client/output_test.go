@@ -0,0 +1,37 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/pflag"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPrintList(t *testing.T) {
+	assert := assert.New(t)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddCountFlag(flags)
+
+	buf := &bytes.Buffer{}
+	PrintList(buf, flags, []string{"a", "b", "c"})
+	assert.Equal("a\nb\nc\n", buf.String())
+
+	assert.NoError(flags.Set("count", "true"))
+	buf.Reset()
+	PrintList(buf, flags, []string{"a", "b", "c"})
+	assert.Equal("3\n", buf.String())
+
+	buf.Reset()
+	PrintList(buf, flags, nil)
+	assert.Equal("0\n", buf.String())
+}
+
+func TestPrintCountWithoutFlag(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	assert.False(PrintCount(buf, pflag.NewFlagSet("test", pflag.ContinueOnError), 3))
+	assert.Equal("", buf.String())
+}
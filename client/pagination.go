@@ -0,0 +1,41 @@
+package client
+
+// Paginate calls fetch repeatedly until the listing it pages through is
+// exhausted. The first call is given an empty continuation token, and each
+// subsequent call is given the continuation token returned by the previous
+// one; fetch is expected to collect the results of each page itself, and
+// return how many it collected. Paginate stops as soon as fetch returns an
+// empty continuation token or an error.
+//
+// If limit is positive, Paginate also stops once limit results have been
+// collected. In that case pageLimit is the number of results still wanted,
+// which fetch should pass on as the per-page limit of the request, and fetch
+// must not collect more than pageLimit results. Otherwise pageLimit is 0.
+func Paginate(limit int, fetch func(continuationToken string, pageLimit int) (next string, count int, err error)) error {
+	continuationToken := ""
+	total := 0
+	for {
+		pageLimit := 0
+		if limit > 0 {
+			pageLimit = limit - total
+		}
+		next, count, err := fetch(continuationToken, pageLimit)
+		if err != nil {
+			return err
+		}
+		total += count
+		if next == "" || (limit > 0 && total >= limit) {
+			return nil
+		}
+		continuationToken = next
+	}
+}
+
+// PageSize returns the number of results to take out of a page of n results,
+// given the pageLimit passed to a Paginate fetch function.
+func PageSize(n, pageLimit int) int {
+	if pageLimit > 0 && pageLimit < n {
+		return pageLimit
+	}
+	return n
+}
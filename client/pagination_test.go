@@ -0,0 +1,77 @@
+package client
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+var pages = map[string]struct {
+	items []string
+	next  string
+}{
+	"":   {[]string{"a", "b"}, "p2"},
+	"p2": {[]string{"c"}, "p3"},
+	"p3": {[]string{"d"}, ""},
+}
+
+func TestPaginate(t *testing.T) {
+	assert := assert.New(t)
+
+	collected := []string{}
+	tokens := []string{}
+	err := Paginate(0, func(continuationToken string, pageLimit int) (string, int, error) {
+		assert.Equal(0, pageLimit, "there should be no page limit without a limit")
+		tokens = append(tokens, continuationToken)
+		page := pages[continuationToken]
+		collected = append(collected, page.items...)
+		return page.next, len(page.items), nil
+	})
+
+	assert.NoError(err)
+	assert.Equal([]string{"", "p2", "p3"}, tokens)
+	assert.Equal([]string{"a", "b", "c", "d"}, collected)
+}
+
+func TestPaginateLimit(t *testing.T) {
+	assert := assert.New(t)
+
+	collected := []string{}
+	pageLimits := []int{}
+	err := Paginate(3, func(continuationToken string, pageLimit int) (string, int, error) {
+		pageLimits = append(pageLimits, pageLimit)
+		page := pages[continuationToken]
+		items := page.items[:PageSize(len(page.items), pageLimit)]
+		collected = append(collected, items...)
+		return page.next, len(items), nil
+	})
+
+	assert.NoError(err)
+	assert.Equal([]int{3, 1}, pageLimits)
+	assert.Equal([]string{"a", "b", "c"}, collected)
+}
+
+func TestPaginateError(t *testing.T) {
+	assert := assert.New(t)
+
+	calls := 0
+	err := Paginate(0, func(continuationToken string, _ int) (string, int, error) {
+		calls++
+		if calls == 2 {
+			return "", 0, errors.New("failed")
+		}
+		return "next", 1, nil
+	})
+
+	assert.Error(err)
+	assert.Equal(2, calls, "pagination should stop at the first error")
+}
+
+func TestPageSize(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(5, PageSize(5, 0))
+	assert.Equal(3, PageSize(5, 3))
+	assert.Equal(5, PageSize(5, 10))
+}
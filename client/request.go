@@ -0,0 +1,22 @@
+package client
+
+import (
+	"fmt"
+	"io"
+)
+
+// PrintRequest writes a description of an HTTP request to out: its method and
+// URL, followed by its JSON body, if there is one. This is how commands that
+// support --dry-run show what they would have sent.
+func PrintRequest(out io.Writer, method, url string, body interface{}) error {
+	fmt.Fprintf(out, "%s %s\n", method, url)
+	if body == nil {
+		return nil
+	}
+	data, err := MarshalJSON(body)
+	if err != nil {
+		return fmt.Errorf("could not marshal request body: %v", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
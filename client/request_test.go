@@ -0,0 +1,20 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPrintRequest(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(PrintRequest(buf, "POST", "https://queue.taskcluster.net/v1/task/abc/cancel", nil))
+	assert.Equal("POST https://queue.taskcluster.net/v1/task/abc/cancel\n", buf.String())
+
+	buf.Reset()
+	assert.NoError(PrintRequest(buf, "PUT", "https://queue.taskcluster.net/v1/task/abc", map[string]string{"workerType": "tutorial"}))
+	assert.Equal("PUT https://queue.taskcluster.net/v1/task/abc\n{\n  \"workerType\": \"tutorial\"\n}\n", buf.String())
+}
@@ -0,0 +1,239 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redactedHeaders are the headers whose values are never written by
+// TraceTransport.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// redactedFields matches the JSON string fields whose values are never written
+// by TraceTransport, such as the access token of credentials.
+var redactedFields = regexp.MustCompile(`("(?:accessToken|certificate|secret|password|token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// redactedQuery matches the query parameters whose values are never written by
+// TraceTransport, such as the bewit of signed URLs.
+var redactedQuery = regexp.MustCompile(`((?:^|&)(?:bewit|X-Amz-Signature)=)[^&]*`)
+
+const redacted = "<redacted>"
+
+// maxTracedBody is how much of a request or response body TraceTransport
+// writes; the rest is left out of the trace.
+const maxTracedBody = 64 * 1024
+
+// TraceTransport is an http.RoundTripper that writes the request line, headers
+// and body of each request, then the status, headers and body of its
+// response, to Out. Credentials and secrets are redacted. Response bodies are
+// passed on to the caller as they arrive, and traced once it is done reading
+// them. Only the first maxTracedBody bytes of textual bodies are traced.
+type TraceTransport struct {
+	// Transport makes the actual requests; http.DefaultTransport is used if
+	// it is nil.
+	Transport http.RoundTripper
+	Out       io.Writer
+
+	mu sync.Mutex
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = peekBody(&req.Body); err != nil {
+			return nil, err
+		}
+		contentType := req.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = http.DetectContentType(reqBody)
+		}
+		if !isText(contentType) {
+			reqBody = nil
+		}
+	}
+
+	resp, err := transport.RoundTrip(req)
+
+	t.write(func(w io.Writer) {
+		fmt.Fprintf(w, "> %s %s %s\n", req.Method, redactURL(req.URL), req.Proto)
+		writeHeader(w, "> ", req.Header)
+		writeBody(w, "> ", reqBody, len(reqBody) > maxTracedBody)
+
+		if err != nil {
+			fmt.Fprintf(w, "< error: %s\n", strings.Replace(err.Error(), req.URL.String(), redactURL(req.URL), -1))
+			return
+		}
+		fmt.Fprintf(w, "< %s %s\n", resp.Proto, resp.Status)
+		writeHeader(w, "< ", resp.Header)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Body != nil && isText(resp.Header.Get("Content-Type")) {
+		resp.Body = &tracedBody{ReadCloser: resp.Body, done: func(body []byte, truncated bool) {
+			if len(body) == 0 {
+				return
+			}
+			t.write(func(w io.Writer) {
+				fmt.Fprintf(w, "< body of %s %s:\n", req.Method, redactURL(req.URL))
+				writeBody(w, "< ", body, truncated)
+			})
+		}}
+	}
+	return resp, nil
+}
+
+// write calls trace with a writer to Out, keeping the trace of concurrent
+// requests from being interleaved.
+func (t *TraceTransport) write(trace func(w io.Writer)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w := bufio.NewWriter(t.Out)
+	trace(w)
+	w.Flush()
+}
+
+// peekBody reads up to maxTracedBody bytes of *body, plus one to tell whether
+// there is more, and replaces *body with a reader over the whole content, so
+// that it can still be consumed.
+func peekBody(body *io.ReadCloser) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(*body, maxTracedBody+1))
+	if err != nil {
+		(*body).Close()
+		return nil, fmt.Errorf("could not read the body for tracing: %v", err)
+	}
+	*body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), *body), *body}
+	return data, nil
+}
+
+// tracedBody records the first maxTracedBody bytes read from a response body,
+// and calls done with them once the body is read in full or closed.
+type tracedBody struct {
+	io.ReadCloser
+	done func(body []byte, truncated bool)
+
+	data      bytes.Buffer
+	truncated bool
+	once      sync.Once
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxTracedBody - b.data.Len(); n > room {
+		b.data.Write(p[:room])
+		b.truncated = true
+	} else {
+		b.data.Write(p[:n])
+	}
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *tracedBody) finish() {
+	b.once.Do(func() { b.done(b.data.Bytes(), b.truncated) })
+}
+
+// isText tells whether bodies of contentType can be written to the trace;
+// binary content, such as most artifacts, is left out.
+func isText(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
+
+// redactURL returns u with the credentials of signed URLs, such as bewits,
+// redacted.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = redactedQuery.ReplaceAllString(u.RawQuery, "${1}"+redacted)
+	return redactedURL.String()
+}
+
+func writeHeader(w io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			switch canonical := http.CanonicalHeaderKey(name); {
+			case redactedHeaders[canonical]:
+				value = redacted
+			case canonical == "Location":
+				// redirects to artifacts carry signed URLs
+				if u, err := url.Parse(value); err == nil {
+					value = redactURL(u)
+				}
+			}
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+	fmt.Fprintln(w, prefix)
+}
+
+// writeBody writes body, the first bytes of the whole body if truncated, with
+// secrets redacted.
+func writeBody(w io.Writer, prefix string, body []byte, truncated bool) {
+	if truncated && len(body) > maxTracedBody {
+		body = body[:maxTracedBody]
+	}
+	if len(body) > 0 {
+		body = redactedFields.ReplaceAll(body, []byte(`$1"`+redacted+`"`))
+		for _, line := range bytes.Split(bytes.TrimRight(body, "\n"), []byte("\n")) {
+			fmt.Fprintf(w, "%s%s\n", prefix, line)
+		}
+	}
+	if truncated {
+		fmt.Fprintf(w, "%s[truncated after %d bytes]\n", prefix, len(body))
+	}
+}
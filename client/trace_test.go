@@ -0,0 +1,163 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestTraceTransport(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=private")
+		io.WriteString(w, `{"echo": `+string(body)+`, "secret": "s3cr3t"}`)
+	}))
+	defer server.Close()
+
+	trace := &bytes.Buffer{}
+	c := &http.Client{Transport: &TraceTransport{Out: trace}}
+
+	req, err := http.NewRequest("POST", server.URL+"/v1/thing", strings.NewReader(`{"accessToken": "hunter2", "scopes": ["a"]}`))
+	assert.NoError(err)
+	req.Header.Set("Authorization", "Hawk id=\"tester\", mac=\"private\"")
+
+	resp, err := c.Do(req)
+	assert.NoError(err)
+	defer resp.Body.Close()
+
+	// the body must still reach both ends untouched
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(err)
+	assert.Equal(`{"echo": {"accessToken": "hunter2", "scopes": ["a"]}, "secret": "s3cr3t"}`, string(body))
+
+	out := trace.String()
+	assert.Contains(out, "> POST "+server.URL+"/v1/thing HTTP/1.1\n")
+	assert.Contains(out, "> Authorization: <redacted>\n")
+	assert.Contains(out, `> {"accessToken": "<redacted>", "scopes": ["a"]}`+"\n")
+	assert.Contains(out, "< HTTP/1.1 200 OK\n")
+	assert.Contains(out, "< Content-Type: application/json\n")
+	assert.Contains(out, "< Set-Cookie: <redacted>\n")
+	assert.Contains(out, `"secret": "<redacted>"`)
+	assert.NotContains(out, "hunter2")
+	assert.NotContains(out, "s3cr3t")
+	assert.NotContains(out, "private")
+}
+
+func TestTraceTransportError(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	trace := &bytes.Buffer{}
+	c := &http.Client{Transport: &TraceTransport{Out: trace}}
+
+	_, err := c.Get(url)
+	assert.Error(err)
+	assert.Contains(trace.String(), "> GET "+url+" HTTP/1.1\n")
+	assert.Contains(trace.String(), "< error: ")
+}
+
+func TestTraceTransportStreams(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, "first line\n")
+		w.(http.Flusher).Flush()
+		<-release
+		io.WriteString(w, "second line\n")
+	}))
+	defer server.Close()
+	defer close(release)
+
+	trace := &bytes.Buffer{}
+	c := &http.Client{Transport: &TraceTransport{Out: trace}}
+
+	resp, err := c.Get(server.URL + "/log")
+	assert.NoError(err)
+	defer resp.Body.Close()
+
+	// the start of the body reaches the caller before the server is done
+	line := make([]byte, len("first line\n"))
+	_, err = io.ReadFull(resp.Body, line)
+	assert.NoError(err)
+	assert.Equal("first line\n", string(line))
+	assert.NotContains(trace.String(), "first line")
+
+	release <- struct{}{}
+	rest, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(err)
+	assert.Equal("second line\n", string(rest))
+	assert.Contains(trace.String(), "< body of GET "+server.URL+"/log:\n< first line\n< second line\n")
+}
+
+func TestTraceTransportSkipsBinary(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		io.WriteString(w, "binary content")
+	}))
+	defer server.Close()
+
+	trace := &bytes.Buffer{}
+	c := &http.Client{Transport: &TraceTransport{Out: trace}}
+
+	resp, err := c.Get(server.URL)
+	assert.NoError(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NoError(err)
+	assert.Equal("binary content", string(body))
+	assert.Contains(trace.String(), "< Content-Type: application/octet-stream\n")
+	assert.NotContains(trace.String(), "binary content")
+}
+
+func TestTraceTransportTruncates(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, strings.Repeat("x", maxTracedBody+10))
+	}))
+	defer server.Close()
+
+	trace := &bytes.Buffer{}
+	c := &http.Client{Transport: &TraceTransport{Out: trace}}
+
+	resp, err := c.Get(server.URL)
+	assert.NoError(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NoError(err)
+	assert.Len(body, maxTracedBody+10)
+	assert.Contains(trace.String(), "< "+strings.Repeat("x", maxTracedBody)+"\n< [truncated after 65536 bytes]\n")
+}
+
+func TestTraceTransportRedactsBewit(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	trace := &bytes.Buffer{}
+	c := &http.Client{Transport: &TraceTransport{Out: trace}}
+
+	resp, err := c.Get(server.URL + "/artifact?a=1&bewit=c2VjcmV0&b=2")
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Contains(trace.String(), "> GET "+server.URL+"/artifact?a=1&bewit=<redacted>&b=2 HTTP/1.1\n")
+	assert.NotContains(trace.String(), "c2VjcmV0")
+}
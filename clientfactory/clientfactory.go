@@ -0,0 +1,116 @@
+// Package clientfactory builds the service clients used by commands, so that
+// they all resolve credentials, base URLs and the HTTP client the same way.
+package clientfactory
+
+import (
+	"os"
+	"strings"
+
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/config"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/auth"
+	"github.com/taskcluster/taskcluster-client-go/hooks"
+	"github.com/taskcluster/taskcluster-client-go/index"
+	"github.com/taskcluster/taskcluster-client-go/queue"
+	"github.com/taskcluster/taskcluster-client-go/secrets"
+)
+
+// ProxyURLEnvVar is the environment variable giving the URL of a taskcluster
+// proxy, such as the one of docker-worker tasks. When it is set the services
+// are reached through the proxy, which signs the requests itself.
+const ProxyURLEnvVar = "TASKCLUSTER_PROXY_URL"
+
+// Factory builds service clients.
+type Factory struct {
+	// Credentials used to sign requests; requests are not signed when nil.
+	Credentials *tcclient.Credentials
+	// HTTPClient sends the requests, client.HTTPClient by default.
+	HTTPClient tcclient.ReducedHTTPClient
+
+	// baseURLs overrides the base URLs of services, by service name.
+	baseURLs map[string]string
+}
+
+// New returns a Factory of clients using credentials and the shared HTTP
+// client.
+func New(credentials *tcclient.Credentials) *Factory {
+	return &Factory{
+		Credentials: credentials,
+		HTTPClient:  client.HTTPClient,
+		baseURLs:    make(map[string]string),
+	}
+}
+
+// FromConfig returns a Factory of clients using the configured credentials, if
+// any.
+func FromConfig() *Factory {
+	var creds *tcclient.Credentials
+	if config.Credentials != nil {
+		creds = config.Credentials.ToClientCredentials()
+	}
+	return New(creds)
+}
+
+// WithBaseURL overrides the base URL of service, e.g. with the value of the
+// --endpoint flag; an empty baseURL leaves the base URL unchanged. It returns
+// the factory so calls can be chained.
+func (f *Factory) WithBaseURL(service, baseURL string) *Factory {
+	if baseURL != "" {
+		f.baseURLs[service] = strings.TrimRight(baseURL, "/")
+	}
+	return f
+}
+
+// Auth returns a client of the auth service.
+func (f *Factory) Auth() *auth.Auth {
+	a := auth.New(f.Credentials)
+	f.configure((*tcclient.Client)(a), "auth")
+	return a
+}
+
+// Hooks returns a client of the hooks service.
+func (f *Factory) Hooks() *hooks.Hooks {
+	h := hooks.New(f.Credentials)
+	f.configure((*tcclient.Client)(h), "hooks")
+	return h
+}
+
+// Index returns a client of the index service.
+func (f *Factory) Index() *index.Index {
+	i := index.New(f.Credentials)
+	f.configure((*tcclient.Client)(i), "index")
+	return i
+}
+
+// Queue returns a client of the queue service.
+func (f *Factory) Queue() *queue.Queue {
+	q := queue.New(f.Credentials)
+	f.configure((*tcclient.Client)(q), "queue")
+	return q
+}
+
+// Secrets returns a client of the secrets service.
+func (f *Factory) Secrets() *secrets.Secrets {
+	s := secrets.New(f.Credentials)
+	f.configure((*tcclient.Client)(s), "secrets")
+	return s
+}
+
+// configure sets up c, a client of service. The base URL is, in order of
+// precedence: the one given to WithBaseURL, the one of the environment
+// variable named by client.EndpointEnvVar, the proxy's, the client's default.
+func (f *Factory) configure(c *tcclient.Client, service string) {
+	c.HTTPClient = f.HTTPClient
+	c.Authenticate = f.Credentials != nil
+
+	if baseURL := f.baseURLs[service]; baseURL != "" {
+		c.BaseURL = baseURL
+	} else if baseURL := strings.TrimRight(os.Getenv(client.EndpointEnvVar(service)), "/"); baseURL != "" {
+		c.BaseURL = baseURL
+	} else if proxy := strings.TrimRight(os.Getenv(ProxyURLEnvVar), "/"); proxy != "" {
+		// the proxy signs requests with the credentials of the task
+		c.BaseURL = proxy + "/" + service + "/v1"
+		c.Authenticate = false
+	}
+}
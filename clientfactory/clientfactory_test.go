@@ -0,0 +1,47 @@
+package clientfactory
+
+import (
+	"os"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	"github.com/taskcluster/taskcluster-cli/client"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+func TestDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	q := New(nil).Queue()
+	assert.Equal("https://queue.taskcluster.net/v1", q.BaseURL)
+	assert.False(q.Authenticate, "requests are not signed without credentials")
+	assert.Equal(client.HTTPClient, q.HTTPClient)
+
+	a := New(&tcclient.Credentials{ClientID: "tester", AccessToken: "secret"}).Auth()
+	assert.Equal("https://auth.taskcluster.net/v1", a.BaseURL)
+	assert.True(a.Authenticate)
+}
+
+func TestBaseURLPrecedence(t *testing.T) {
+	assert := assert.New(t)
+
+	defer os.Unsetenv(ProxyURLEnvVar)
+	defer os.Unsetenv("TASKCLUSTER_INDEX_ENDPOINT")
+	creds := &tcclient.Credentials{ClientID: "tester", AccessToken: "secret"}
+
+	os.Setenv(ProxyURLEnvVar, "http://taskcluster/")
+	s := New(creds).Secrets()
+	assert.Equal("http://taskcluster/secrets/v1", s.BaseURL)
+	assert.False(s.Authenticate, "the proxy signs the requests")
+
+	os.Setenv("TASKCLUSTER_INDEX_ENDPOINT", "http://localhost:8080/v1")
+	i := New(creds).Index()
+	assert.Equal("http://localhost:8080/v1", i.BaseURL, "endpoints override the proxy")
+	assert.True(i.Authenticate)
+
+	i = New(creds).WithBaseURL("index", "http://localhost:9090/v1/").WithBaseURL("hooks", "http://unused").Index()
+	assert.Equal("http://localhost:9090/v1", i.BaseURL, "explicit base URLs override everything")
+
+	h := New(creds).WithBaseURL("hooks", "").Hooks()
+	assert.Equal("http://taskcluster/hooks/v1", h.BaseURL, "empty base URLs are ignored")
+}
@@ -0,0 +1,99 @@
+package configCmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/shibukawa/configdir"
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/config"
+)
+
+// redactedOptions are the configuration options whose values are never shown.
+var redactedOptions = map[string]bool{
+	"config.accessToken": true,
+}
+
+func init() {
+	Command.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Show the effective configuration and where each value comes from.",
+		Long: `Show the effective configuration: the configuration file and cache folder in
+use, whether credentials are present and where they were loaded from, and the
+value of each configuration option along with its source, which is one of:
+
+  env      an environment variable, which takes precedence over the file
+  file     the configuration file
+  default  the default value
+
+Credentials loaded from a file given with --credentials-file or
+` + config.CredentialsFileEnvVar + ` take precedence over the clientId and
+accessToken options, unless those come from the environment. Access tokens
+are redacted.`,
+		RunE: cmdShow,
+	})
+}
+
+func cmdShow(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+
+	file := config.FilePath()
+	if _, err := os.Stat(file); err == nil {
+		fmt.Fprintf(out, "Configuration file: %s\n", file)
+	} else {
+		fmt.Fprintf(out, "Configuration file: %s (not found)\n", file)
+	}
+	// this is the cache folder of commands such as status
+	cache := configdir.New("taskcluster", "taskcluster-cli").QueryCacheFolder()
+	fmt.Fprintf(out, "Cache folder:       %s\n", cache.Path)
+	if c := config.Credentials; c != nil && c.ClientID != "" && c.AccessToken != "" {
+		kind := "permanent"
+		if c.Certificate != "" {
+			kind = "temporary"
+		}
+		fmt.Fprintf(out, "Credentials:        %s, clientId %s (%s)\n", kind, c.ClientID, config.CredentialsSource)
+	} else {
+		fmt.Fprintln(out, "Credentials:        none")
+	}
+	fmt.Fprintln(out)
+
+	keys := []string{}
+	for command, options := range config.OptionsDefinitions {
+		for option := range options {
+			keys = append(keys, command+"."+option)
+		}
+	}
+	sort.Strings(keys)
+
+	width := 0
+	for _, key := range keys {
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+	for _, key := range keys {
+		command, option, definition, value, err := getOptionFromKey(key)
+		if err != nil {
+			return err
+		}
+
+		shown := "<redacted>"
+		if !redactedOptions[key] || reflect.DeepEqual(value, definition.Default) {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("could not marshal the value of %s: %s", key, err)
+			}
+			shown = string(data)
+		}
+
+		source := string(config.Sources[command][option])
+		if config.Sources[command][option] == config.SourceEnv {
+			source += " " + definition.Env
+		}
+		fmt.Fprintf(out, "%s  %s  (%s)\n", pad(key, width), shown, source)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package configCmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/config"
+)
+
+func TestShow(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "taskcluster.yml"), []byte("config:\n  clientId: from-file\n  accessToken: s3cr3t\n"), 0600))
+	defer os.Setenv("TASKCLUSTER_CLIENT_ID", os.Getenv("TASKCLUSTER_CLIENT_ID"))
+	os.Setenv("TASKCLUSTER_CLIENT_ID", "from-env")
+	for _, name := range []string{"TASKCLUSTER_ACCESS_TOKEN", "TASKCLUSTER_CERTIFICATE"} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+
+	defer func(c map[string]map[string]interface{}, s map[string]map[string]config.Source) {
+		config.Configuration, config.Sources = c, s
+	}(config.Configuration, config.Sources)
+	config.Configuration, config.Sources, err = config.LoadWithSources()
+	assert.NoError(err)
+
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	assert.NoError(cmdShow(cmd, nil))
+
+	out := buf.String()
+	assert.Contains(out, "Configuration file: "+filepath.Join(dir, "taskcluster.yml")+"\n")
+	assert.Regexp(`config\.clientId +"from-env"  \(env TASKCLUSTER_CLIENT_ID\)`, out)
+	assert.Regexp(`config\.accessToken +<redacted>  \(file\)`, out)
+	assert.Regexp(`config\.certificate +null  \(default\)`, out)
+	assert.NotContains(out, "s3cr3t")
+}
+
+func TestShowCredentialsSource(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(c *client.Credentials, s string) {
+		config.Credentials, config.CredentialsSource = c, s
+	}(config.Credentials, config.CredentialsSource)
+	config.Credentials = &client.Credentials{ClientID: "tester", AccessToken: "s3cr3t"}
+	config.CredentialsSource = config.CredentialsFileEnvVar + " /run/secrets/taskcluster.json"
+
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	assert.NoError(cmdShow(cmd, nil))
+	assert.Contains(buf.String(), "Credentials:        permanent, clientId tester (TASKCLUSTER_CREDENTIALS_FILE /run/secrets/taskcluster.json)\n")
+	assert.NotContains(buf.String(), "s3cr3t")
+}
+
+func TestShowNoCredentials(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	for _, name := range []string{"TASKCLUSTER_CLIENT_ID", "TASKCLUSTER_ACCESS_TOKEN", "TASKCLUSTER_CERTIFICATE", config.CredentialsFileEnvVar} {
+		defer os.Setenv(name, os.Getenv(name))
+		os.Unsetenv(name)
+	}
+
+	defer func(c map[string]map[string]interface{}, s map[string]map[string]config.Source, creds *client.Credentials, source string) {
+		config.Configuration, config.Sources = c, s
+		config.Credentials, config.CredentialsSource = creds, source
+	}(config.Configuration, config.Sources, config.Credentials, config.CredentialsSource)
+	// the clientId and accessToken options default to empty strings
+	config.Setup()
+	assert.Nil(config.Credentials)
+
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	assert.NoError(cmdShow(cmd, nil))
+	assert.Contains(buf.String(), "Credentials:        none\n")
+}
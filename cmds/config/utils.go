@@ -1,11 +1,11 @@
 package configCmd
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/taskcluster/taskcluster-cli/client"
 	"github.com/taskcluster/taskcluster-cli/config"
 	"gopkg.in/yaml.v2"
 )
@@ -69,7 +69,7 @@ func formatYAML(value interface{}) []byte {
 }
 
 func formatJSON(value interface{}) []byte {
-	data, err := json.MarshalIndent(value, "", "  ")
+	data, err := client.MarshalJSON(value)
 	if err != nil {
 		panic(fmt.Sprintf("Internal error rendering json, error: %s", err))
 	}
@@ -0,0 +1,100 @@
+package decodeCertificate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+
+	"github.com/spf13/cobra"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+// stdin is where the certificate is read from when neither the flag nor the
+// environment variable are set; it is a variable so tests can replace it.
+var stdin io.Reader = os.Stdin
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "decode-certificate",
+		Short: "Decodes the certificate of temporary credentials.",
+		Long: `Decodes the certificate of temporary credentials and prints its version,
+scopes, start and expiry times, seed and whether it is named.
+
+The certificate is read from the --certificate flag, or from the
+TASKCLUSTER_CERTIFICATE environment variable, or from stdin, in that order.
+No network calls are made.`,
+		RunE: decodeCertificate,
+	}
+	cmd.Flags().StringP("certificate", "c", "", "Certificate to decode, as a JSON string.")
+
+	root.Command.AddCommand(cmd)
+}
+
+func decodeCertificate(cmd *cobra.Command, _ []string) error {
+	// find the certificate: flag, then environment, then stdin
+	data, _ := cmd.Flags().GetString("certificate")
+	if data == "" {
+		data = os.Getenv("TASKCLUSTER_CERTIFICATE")
+	}
+	if data == "" {
+		d, err := ioutil.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read certificate from stdin, error: %s", err)
+		}
+		data = string(d)
+	}
+	if strings.TrimSpace(data) == "" {
+		return errors.New("decode-certificate requires a certificate")
+	}
+
+	var cert tcclient.Certificate
+	if err := json.Unmarshal([]byte(data), &cert); err != nil {
+		return fmt.Errorf("failed to parse certificate, error: %s", err)
+	}
+
+	printCertificate(cmd.OutOrStdout(), &cert, time.Now())
+	return nil
+}
+
+// printCertificate writes a human readable description of cert to out, with
+// times described relative to now.
+func printCertificate(out io.Writer, cert *tcclient.Certificate, now time.Time) {
+	start := fromMillis(cert.Start)
+	expiry := fromMillis(cert.Expiry)
+
+	fmt.Fprintf(out, "Version: %d\n", cert.Version)
+	fmt.Fprintln(out, "Scopes:")
+	for _, scope := range cert.Scopes {
+		fmt.Fprintf(out, "  * %s\n", scope)
+	}
+	fmt.Fprintf(out, "Start:   %s (%s)\n", start.Format(time.RFC3339), relative(start, now))
+	fmt.Fprintf(out, "Expiry:  %s (%s)\n", expiry.Format(time.RFC3339), relative(expiry, now))
+	fmt.Fprintf(out, "Seed:    %s\n", cert.Seed)
+	if cert.Issuer != "" {
+		fmt.Fprintf(out, "Named:   yes (issuer: %s)\n", cert.Issuer)
+	} else {
+		fmt.Fprintln(out, "Named:   no")
+	}
+}
+
+// fromMillis converts milliseconds since the epoch, as used in certificates,
+// into a UTC time.
+func fromMillis(ms int64) time.Time {
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC()
+}
+
+// relative describes t relative to now, e.g. "in 5m0s" or "2h0m0s ago".
+func relative(t, now time.Time) string {
+	d := t.Sub(now) / time.Second * time.Second
+	if d < 0 {
+		return fmt.Sprintf("%s ago", -d)
+	}
+	return fmt.Sprintf("in %s", d)
+}
@@ -0,0 +1,91 @@
+package decodeCertificate
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+const fakeCertificate = `{
+	"version": 1,
+	"scopes": ["queue:create-task:*", "assume:project:taskcluster"],
+	"start": 1491901200000,
+	"expiry": 1491904800000,
+	"seed": "fake-seed",
+	"signature": "fake-signature",
+	"issuer": "mozilla-ldap/someone"
+}`
+
+func setUpCommand() (*bytes.Buffer, *cobra.Command) {
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	cmd.Flags().StringP("certificate", "c", "", "")
+
+	return buf, cmd
+}
+
+func TestPrintCertificate(t *testing.T) {
+	assert := assert.New(t)
+
+	cert := &tcclient.Certificate{
+		Version: 1,
+		Scopes:  []string{"queue:*"},
+		Start:   1491901200000,
+		Expiry:  1491904800000,
+		Seed:    "fake-seed",
+	}
+	now := time.Unix(1491902100, 0)
+
+	buf := &bytes.Buffer{}
+	printCertificate(buf, cert, now)
+
+	assert.Equal(
+		"Version: 1\n"+
+			"Scopes:\n"+
+			"  * queue:*\n"+
+			"Start:   2017-04-11T09:00:00Z (15m0s ago)\n"+
+			"Expiry:  2017-04-11T10:00:00Z (in 45m0s)\n"+
+			"Seed:    fake-seed\n"+
+			"Named:   no\n",
+		buf.String(),
+	)
+}
+
+func TestDecodeCertificateFlag(t *testing.T) {
+	assert := assert.New(t)
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("certificate", fakeCertificate)
+
+	assert.NoError(decodeCertificate(cmd, nil))
+	assert.Contains(buf.String(), "  * assume:project:taskcluster\n")
+	assert.Contains(buf.String(), "Named:   yes (issuer: mozilla-ldap/someone)\n")
+}
+
+func TestDecodeCertificateStdin(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader(fakeCertificate)
+
+	buf, cmd := setUpCommand()
+
+	assert.NoError(decodeCertificate(cmd, nil))
+	assert.Contains(buf.String(), "Seed:    fake-seed\n")
+}
+
+func TestDecodeCertificateInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, cmd := setUpCommand()
+	cmd.Flags().Set("certificate", "not json")
+
+	assert.Error(decodeCertificate(cmd, nil), "invalid certificates should produce an error")
+}
@@ -0,0 +1,210 @@
+package expandScope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+	"github.com/taskcluster/taskcluster-cli/config"
+	"github.com/taskcluster/taskcluster-cli/scopes"
+	"github.com/taskcluster/taskcluster-cli/timeparse"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/auth"
+)
+
+// allow overriding the base URL, with --endpoint or for testing
+var authBaseURL string
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "expand-scope [<scope>...]",
+		Short: "Expands scopes, including the scopes granted by the roles they assume.",
+		Long: `Expands the given scopes with the auth service, and prints the resulting
+scopes, sorted. Scopes of the form assume:<roleId> are expanded into the scopes
+of the matching roles, which is handy to preview what a role grants.
+
+With --from-client, the scopes of an existing client are expanded, to answer
+what that client can do.`,
+		RunE: expandScope,
+	}
+	cmd.Flags().StringArray("assume", nil, "Expand the role with this roleId, same as passing assume:<roleId> (repeatable).")
+	cmd.Flags().String("from-client", "", "Expand the scopes of the client with this clientId, as given by the auth service; this requires credentials.")
+	cmd.Flags().Bool("fail-on-empty", false, "Fail if there are no scopes to expand, e.g. because --from-client has none, rather than printing nothing.")
+	cmd.Flags().Bool("diff-input", false, "Only print the scopes gained from the expansion, that were not part of the input.")
+	cmd.Flags().Bool("expand-roles", true, "Expand the roles with the auth service; with --expand-roles=false the scopes are only normalized locally, without any network call.")
+	cmd.Flags().Bool("minimize", false, "Drop the scopes already satisfied by another scope of the result ending with a '*'.")
+	timeparse.AddDurationFlag(cmd.Flags(), "timeout", time.Minute, "Give up on the auth service after this long.")
+	cmd.Flags().Bool("hierarchy", false, "Print the scopes as a tree, grouped by their common prefixes.")
+	client.AddCountFlag(cmd.Flags())
+	client.AddEndpointFlag(cmd.Flags(), "auth")
+
+	root.Command.AddCommand(cmd)
+}
+
+func makeAuth(ctx context.Context, credentials *tcclient.Credentials) *auth.Auth {
+	// expanding scopes doesn't require credentials, so they may be nil
+	f := clientfactory.New(credentials).WithBaseURL("auth", authBaseURL)
+	f.HTTPClient = &client.ContextClient{Context: ctx, Client: client.HTTPClient}
+	return f.Auth()
+}
+
+func expandScope(cmd *cobra.Command, args []string) error {
+	input, err := inputScopes(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	var creds *tcclient.Credentials
+	if config.Credentials != nil && config.Credentials.ClientID != "" {
+		creds = config.Credentials.ToClientCredentials()
+	}
+	if endpoint := client.Endpoint(cmd.Flags(), "auth"); endpoint != "" {
+		authBaseURL = endpoint
+	}
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return fmt.Errorf("--timeout must be positive, got %v", timeout)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	ctx, stop := client.InterruptContext(ctx)
+	defer stop()
+
+	if clientID, _ := cmd.Flags().GetString("from-client"); clientID != "" {
+		if creds == nil {
+			return errors.New("--from-client requires credentials, with the scope auth:get-client:" + clientID + " if the client isn't yours")
+		}
+		clientScopes, err := fetchClientScopes(ctx, creds, clientID)
+		if err != nil {
+			return err
+		}
+		input = append(input, clientScopes...)
+	}
+	if failOnEmpty, _ := cmd.Flags().GetBool("fail-on-empty"); failOnEmpty && len(input) == 0 {
+		return errors.New("there are no scopes to expand")
+	}
+
+	var expanded []string
+	if expandRoles, _ := cmd.Flags().GetBool("expand-roles"); expandRoles {
+		if expanded, err = expand(ctx, creds, input); err != nil {
+			return err
+		}
+	} else {
+		expanded = scopes.Normalize(input)
+	}
+	if minimize, _ := cmd.Flags().GetBool("minimize"); minimize {
+		expanded = scopes.Normalize(expanded)
+	}
+	if diffInput, _ := cmd.Flags().GetBool("diff-input"); diffInput {
+		expanded = difference(expanded, input)
+	}
+
+	out := cmd.OutOrStdout()
+	if client.PrintCount(out, cmd.Flags(), len(expanded)) {
+		return nil
+	}
+	if hierarchy, _ := cmd.Flags().GetBool("hierarchy"); hierarchy {
+		printHierarchy(out, expanded)
+		return nil
+	}
+	client.PrintList(out, cmd.Flags(), expanded)
+	return nil
+}
+
+// inputScopes returns the scopes to expand: the arguments, followed by an
+// assume:<roleId> scope for each --assume.
+func inputScopes(cmd *cobra.Command, args []string) ([]string, error) {
+	if err := validateScopes(args); err != nil {
+		return nil, err
+	}
+	scopes := append([]string{}, args...)
+	roles, _ := cmd.Flags().GetStringArray("assume")
+	for _, roleID := range roles {
+		if strings.TrimSpace(roleID) == "" {
+			return nil, errors.New("--assume requires a roleId, got an empty one")
+		}
+		scopes = append(scopes, "assume:"+roleID)
+	}
+	if clientID, _ := cmd.Flags().GetString("from-client"); len(scopes) == 0 && clientID == "" {
+		return nil, errors.New("expand-scope requires at least one scope, --assume <roleId> or --from-client <clientId>")
+	}
+	return scopes, nil
+}
+
+// validateScopes rejects the empty and whitespace-only scopes, which are
+// rather the sign of an unset variable in a script than actual scopes.
+func validateScopes(scopes []string) error {
+	for i, scope := range scopes {
+		if strings.TrimSpace(scope) == "" {
+			return fmt.Errorf("invalid scope %q at position %d, scopes can't be empty or only whitespace", scope, i+1)
+		}
+	}
+	return nil
+}
+
+// expand returns the scopes granted by scopes, including those of the roles
+// they assume, sorted. It gives up as soon as ctx is done.
+func expand(ctx context.Context, credentials *tcclient.Credentials, scopes []string) ([]string, error) {
+	a := makeAuth(ctx, credentials)
+	var result *auth.SetOfScopes
+	err := client.CallWithContext(ctx, func() (err error) {
+		result, err = a.ExpandScopes(&auth.SetOfScopes{Scopes: scopes})
+		return
+	})
+	switch err {
+	case nil:
+	case context.DeadlineExceeded:
+		return nil, errors.New("timed out waiting for the auth service to expand the scopes, see --timeout")
+	case context.Canceled:
+		return nil, errors.New("interrupted while expanding the scopes")
+	default:
+		return nil, fmt.Errorf("could not expand the scopes: %v", err)
+	}
+	expanded := append([]string{}, result.Scopes...)
+	sort.Strings(expanded)
+	return expanded, nil
+}
+
+// fetchClientScopes returns the expanded scopes of the client clientID. It
+// gives up as soon as ctx is done.
+func fetchClientScopes(ctx context.Context, credentials *tcclient.Credentials, clientID string) ([]string, error) {
+	a := makeAuth(ctx, credentials)
+	var result *auth.GetClientResponse
+	err := client.CallWithContext(ctx, func() (err error) {
+		result, err = a.Client(clientID)
+		return
+	})
+	switch err {
+	case nil:
+	case context.DeadlineExceeded:
+		return nil, errors.New("timed out waiting for the auth service to get the client, see --timeout")
+	case context.Canceled:
+		return nil, errors.New("interrupted while getting the client")
+	default:
+		return nil, fmt.Errorf("could not get the scopes of client %s: %v", clientID, err)
+	}
+	return result.ExpandedScopes, nil
+}
+
+// difference returns the scopes of a which are not in b.
+func difference(a, b []string) []string {
+	exclude := make(map[string]bool, len(b))
+	for _, scope := range b {
+		exclude[scope] = true
+	}
+	result := []string{}
+	for _, scope := range a {
+		if !exclude[scope] {
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,262 @@
+package expandScope
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/config"
+)
+
+type FakeServerSuite struct {
+	suite.Suite
+	testServer *httptest.Server
+}
+
+func (suite *FakeServerSuite) SetupSuite() {
+	// set up a fake server that knows how to answer the `expandScopes()` method
+	handler := http.NewServeMux()
+	handler.HandleFunc("/v1/scopes/expand", expandScopesHandler)
+	handler.HandleFunc("/v1/clients/project/tester", clientHandler)
+	handler.HandleFunc("/v1/clients/project/idle", idleClientHandler)
+
+	suite.testServer = httptest.NewServer(handler)
+
+	// set the base URL the command uses to point to the fake server
+	authBaseURL = suite.testServer.URL + "/v1"
+}
+
+func (suite *FakeServerSuite) TearDownSuite() {
+	suite.testServer.Close()
+	authBaseURL = ""
+}
+
+func TestFakeServerSuite(t *testing.T) {
+	suite.Run(t, new(FakeServerSuite))
+}
+
+// knows a single role, project:taskcluster
+func expandScopesHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Scopes []string `json:"scopes"`
+	}
+	json.NewDecoder(r.Body).Decode(&payload)
+
+	scopes := payload.Scopes
+	for _, scope := range payload.Scopes {
+		if scope == "assume:project:taskcluster" {
+			scopes = append(scopes, "secrets:get:project/taskcluster/*", "queue:create-task:aws-provisioner-v1/tutorial")
+		}
+	}
+	// like the auth service, return a set
+	set := []string{}
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			set = append(set, scope)
+		}
+	}
+	json.NewEncoder(w).Encode(map[string][]string{"scopes": set})
+}
+
+// knows a single client, project/tester, who can assume project:taskcluster
+func clientHandler(w http.ResponseWriter, _ *http.Request) {
+	io.WriteString(w, `{
+		"clientId": "project/tester",
+		"scopes": ["assume:project:taskcluster"],
+		"expandedScopes": ["assume:project:taskcluster", "secrets:get:project/taskcluster/*"]
+	}`)
+}
+
+// knows a client without any scope, project/idle
+func idleClientHandler(w http.ResponseWriter, _ *http.Request) {
+	io.WriteString(w, `{"clientId": "project/idle", "scopes": [], "expandedScopes": []}`)
+}
+
+func setUpCommand() (*bytes.Buffer, *cobra.Command) {
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	cmd.Flags().StringArray("assume", nil, "")
+	cmd.Flags().String("from-client", "", "")
+	cmd.Flags().Bool("fail-on-empty", false, "")
+	cmd.Flags().Bool("diff-input", false, "")
+	cmd.Flags().Bool("count", false, "")
+	cmd.Flags().Bool("hierarchy", false, "")
+	cmd.Flags().Bool("expand-roles", true, "")
+	cmd.Flags().Bool("minimize", false, "")
+	cmd.Flags().Duration("timeout", time.Minute, "")
+
+	return buf, cmd
+}
+
+func (suite *FakeServerSuite) TestExpandScope() {
+	buf, cmd := setUpCommand()
+
+	suite.NoError(expandScope(cmd, []string{"queue:get-artifact:*", "assume:project:taskcluster"}))
+	suite.Equal("assume:project:taskcluster\n"+
+		"queue:create-task:aws-provisioner-v1/tutorial\n"+
+		"queue:get-artifact:*\n"+
+		"secrets:get:project/taskcluster/*\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpandScopeAssume() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("assume", "project:taskcluster")
+
+	suite.NoError(expandScope(cmd, nil))
+	suite.Equal("assume:project:taskcluster\n"+
+		"queue:create-task:aws-provisioner-v1/tutorial\n"+
+		"secrets:get:project/taskcluster/*\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpandScopeEmpty() {
+	_, cmd := setUpCommand()
+
+	suite.Error(expandScope(cmd, nil), "a scope or a role is required")
+}
+
+func (suite *FakeServerSuite) TestExpandScopeDiffInput() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("diff-input", "true")
+
+	suite.NoError(expandScope(cmd, []string{"queue:get-artifact:*", "assume:project:taskcluster"}))
+	suite.Equal("queue:create-task:aws-provisioner-v1/tutorial\n"+
+		"secrets:get:project/taskcluster/*\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpandScopeCount() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("count", "true")
+
+	suite.NoError(expandScope(cmd, []string{"queue:get-artifact:*", "assume:project:taskcluster"}))
+	suite.Equal("4\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpand() {
+	expanded, err := expand(context.Background(), nil, []string{"assume:project:taskcluster"})
+	suite.NoError(err)
+	suite.Equal([]string{
+		"assume:project:taskcluster",
+		"queue:create-task:aws-provisioner-v1/tutorial",
+		"secrets:get:project/taskcluster/*",
+	}, expanded)
+}
+
+func (suite *FakeServerSuite) TestExpandScopeHierarchy() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("hierarchy", "true")
+
+	suite.NoError(expandScope(cmd, []string{"queue:get-artifact:*", "assume:project:taskcluster"}))
+	suite.Equal("assume:\n"+
+		"  project:\n"+
+		"    taskcluster\n"+
+		"queue:\n"+
+		"  create-task:\n"+
+		"    aws-provisioner-v1/tutorial\n"+
+		"  get-artifact:\n"+
+		"    *\n"+
+		"secrets:\n"+
+		"  get:\n"+
+		"    project/taskcluster/\n"+
+		"      *\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpandScopeWithoutRoles() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("expand-roles", "false")
+
+	// the fake server isn't called, so the role is left as is
+	defer func(u string) { authBaseURL = u }(authBaseURL)
+	authBaseURL = "http://127.0.0.1:1"
+
+	suite.NoError(expandScope(cmd, []string{"queue:get-artifact:public/x", "assume:project:taskcluster", "queue:get-artifact:*"}))
+	suite.Equal("assume:project:taskcluster\n"+
+		"queue:get-artifact:*\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpandScopeMinimize() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("minimize", "true")
+
+	suite.NoError(expandScope(cmd, []string{"queue:*", "assume:project:taskcluster"}))
+	suite.Equal("assume:project:taskcluster\n"+
+		"queue:*\n"+
+		"secrets:get:project/taskcluster/*\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpandScopeTimeout() {
+	_, cmd := setUpCommand()
+	cmd.Flags().Set("timeout", "50ms")
+
+	release := make(chan struct{})
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer hung.Close()
+	defer close(release)
+	defer func(u string) { authBaseURL = u }(authBaseURL)
+	authBaseURL = hung.URL + "/v1"
+
+	err := expandScope(cmd, []string{"assume:project:taskcluster"})
+	suite.Error(err)
+	suite.Contains(err.Error(), "timed out")
+}
+
+func (suite *FakeServerSuite) TestExpandScopeFromClient() {
+	defer func(c *client.Credentials) { config.Credentials = c }(config.Credentials)
+	config.Credentials = &client.Credentials{ClientID: "project/tester", AccessToken: "secret"}
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("from-client", "project/tester")
+
+	suite.NoError(expandScope(cmd, nil))
+	suite.Equal("assume:project:taskcluster\n"+
+		"queue:create-task:aws-provisioner-v1/tutorial\n"+
+		"secrets:get:project/taskcluster/*\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestExpandScopeFromClientWithoutCredentials() {
+	defer func(c *client.Credentials) { config.Credentials = c }(config.Credentials)
+	for _, creds := range []*client.Credentials{nil, {}} {
+		config.Credentials = creds
+
+		_, cmd := setUpCommand()
+		cmd.Flags().Set("from-client", "project/tester")
+
+		err := expandScope(cmd, nil)
+		suite.Error(err)
+		suite.Contains(err.Error(), "--from-client requires credentials")
+	}
+}
+
+func (suite *FakeServerSuite) TestExpandScopeFailOnEmpty() {
+	defer func(c *client.Credentials) { config.Credentials = c }(config.Credentials)
+	config.Credentials = &client.Credentials{ClientID: "project/tester", AccessToken: "secret"}
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("from-client", "project/idle")
+	suite.NoError(expandScope(cmd, nil))
+	suite.Empty(buf.String())
+
+	cmd.Flags().Set("fail-on-empty", "true")
+	suite.Error(expandScope(cmd, nil), "the client has no scopes to expand")
+}
+
+func (suite *FakeServerSuite) TestExpandScopeInvalidInput() {
+	_, cmd := setUpCommand()
+	suite.Error(expandScope(cmd, []string{"queue:*", ""}), "empty scopes are rejected")
+	suite.Error(expandScope(cmd, []string{" \t"}), "whitespace-only scopes are rejected")
+
+	cmd.Flags().Set("assume", "")
+	suite.Error(expandScope(cmd, nil), "empty roleIds are rejected")
+}
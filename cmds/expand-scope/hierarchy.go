@@ -0,0 +1,68 @@
+package expandScope
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// scopeTree is a node of the tree of scopes printed with --hierarchy; each
+// child is keyed by its segment of the scopes below it.
+type scopeTree map[string]scopeTree
+
+// segments splits scope after each ':', and before a '*', so that scopes are
+// grouped by their common prefix, e.g. "queue:get-artifact:public/*" gives
+// "queue:", "get-artifact:", "public/" and "*".
+func segments(scope string) []string {
+	result := []string{}
+	start := 0
+	for i, c := range scope {
+		switch c {
+		case ':':
+			result = append(result, scope[start:i+1])
+			start = i + 1
+		case '*':
+			if i > start {
+				result = append(result, scope[start:i])
+			}
+			start = i
+		}
+	}
+	if start < len(scope) {
+		result = append(result, scope[start:])
+	}
+	return result
+}
+
+// newScopeTree returns the tree of the segments of scopes.
+func newScopeTree(scopes []string) scopeTree {
+	tree := scopeTree{}
+	for _, scope := range scopes {
+		node := tree
+		for _, segment := range segments(scope) {
+			if node[segment] == nil {
+				node[segment] = scopeTree{}
+			}
+			node = node[segment]
+		}
+	}
+	return tree
+}
+
+// printHierarchy writes scopes to out as an indented tree of their segments.
+func printHierarchy(out io.Writer, scopes []string) {
+	newScopeTree(scopes).print(out, 0)
+}
+
+func (tree scopeTree) print(out io.Writer, depth int) {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(out, "%s%s\n", strings.Repeat("  ", depth), key)
+		tree[key].print(out, depth+1)
+	}
+}
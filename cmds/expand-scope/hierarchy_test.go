@@ -0,0 +1,16 @@
+package expandScope
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSegments(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"queue:", "get-artifact:", "public/", "*"}, segments("queue:get-artifact:public/*"))
+	assert.Equal([]string{"queue:", "*"}, segments("queue:*"))
+	assert.Equal([]string{"*"}, segments("*"))
+	assert.Equal([]string{"assume:", "project:", "taskcluster"}, segments("assume:project:taskcluster"))
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/url"
 	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 	"github.com/taskcluster/taskcluster-client-go/queue"
 )
@@ -20,18 +23,16 @@ func init() {
 	}
 	cancelCmd.Flags().StringP("worker-type", "w", "", "Only cancel tasks with a certain worker type.")
 	cancelCmd.Flags().BoolP("force", "f", false, "Skip cancellation confirmation.")
+	cancelCmd.Flags().BoolP("dry-run", "d", false, "Print the cancellation requests that would be made instead of making them.")
 
 	Command.AddCommand(cancelCmd)
 }
 
+// allow overriding the base URL, with --endpoint or for testing
 var queueBaseURL string
 
 func makeQueue(credentials *tcclient.Credentials) *queue.Queue {
-	q := queue.New(credentials)
-	if queueBaseURL != "" {
-		q.BaseURL = queueBaseURL
-	}
-	return q
+	return clientfactory.New(credentials).WithBaseURL("queue", queueBaseURL).Queue()
 }
 
 // runCancel cancels all tasks of a group.
@@ -48,13 +49,12 @@ func runCancel(credentials *tcclient.Credentials, args []string, out io.Writer,
 	// we are told not to.
 	tasks := make([]string, 0)
 	tasksNames := make([]string, 0)
-	cont := ""
 
-	for {
+	err := client.Paginate(0, func(continuationToken string, _ int) (string, int, error) {
 		// get next TaskGroup for groupID
-		ts, err := q.ListTaskGroup(groupID, cont, "")
+		ts, err := q.ListTaskGroup(groupID, continuationToken, "")
 		if err != nil {
-			return fmt.Errorf("could not fetch tasks for group %s: %v", groupID, err)
+			return "", 0, fmt.Errorf("could not fetch tasks for group %s: %v", groupID, err)
 		}
 
 		// set tasks that meet the criteria (see filterTask) to be deleted
@@ -65,11 +65,10 @@ func runCancel(credentials *tcclient.Credentials, args []string, out io.Writer,
 				tasksNames = append(tasksNames, t.Task.Metadata.Name)
 			}
 		}
-
-		// break if there are no more tasks for that groupID
-		if cont = ts.ContinuationToken; cont == "" {
-			break
-		}
+		return ts.ContinuationToken, len(ts.Tasks), nil
+	})
+	if err != nil {
+		return err
 	}
 
 	if len(tasks) == 0 {
@@ -77,6 +76,15 @@ func runCancel(credentials *tcclient.Credentials, args []string, out io.Writer,
 		return nil
 	}
 
+	if dryRun, _ := flags.GetBool("dry-run"); dryRun {
+		for _, taskID := range tasks {
+			if err := client.PrintRequest(out, "POST", q.BaseURL+"/task/"+url.QueryEscape(taskID)+"/cancel", nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
 	// ask for confirmation before cancellation
 	if force, _ := flags.GetBool("force"); !force && !confirmCancellation(tasks, tasksNames, out) {
 		fmt.Fprintln(out, "Cancellation of tasks aborted.")
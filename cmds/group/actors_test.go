@@ -27,6 +27,7 @@ func (suite *FakeServerSuite) SetupSuite() {
 
 	handler.HandleFunc("/v1/task/"+fakeTaskID+"/cancel", cancelHandler)
 	handler.HandleFunc("/v1/task-group/"+fakeGroupID+"/list", listTaskGroupHandler)
+	handler.HandleFunc("/v1/task-group/"+fakeStatusGroupID+"/list", statusTaskGroupHandler)
 
 	suite.testServer = httptest.NewServer(handler)
 
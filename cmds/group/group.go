@@ -2,6 +2,7 @@ package group
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/client"
 	"github.com/taskcluster/taskcluster-cli/cmds/root"
 )
 
@@ -14,5 +15,6 @@ var (
 )
 
 func init() {
+	client.AddEndpointFlag(Command.PersistentFlags(), "queue")
 	root.Command.AddCommand(Command)
 }
@@ -0,0 +1,211 @@
+package group
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/queue"
+)
+
+// taskStates are the states a task can be in, in the order they are
+// summarized.
+var taskStates = []string{"unscheduled", "pending", "running", "completed", "failed", "exception"}
+
+func init() {
+	statusCmd := &cobra.Command{
+		Use:   "status <taskGroupId>",
+		Short: "Summarize the states of the tasks of a group and list them.",
+		Long: `Summarize the states of the tasks of a group and list them.
+
+The summary always covers the whole group, while --filter and --name-match
+restrict the tasks which are listed, e.g. to find the failing ones:
+
+  taskcluster group status --filter failed,exception <taskGroupId>
+
+With --format ndjson, the listed tasks are written as they are fetched, as
+one JSON object per line, without the summary, so that large groups can be
+processed incrementally.`,
+		RunE: executeHelperE(runStatus),
+	}
+	statusCmd.Flags().StringSlice("filter", nil, "Only list the tasks in these states ("+strings.Join(taskStates, ", ")+").")
+	statusCmd.Flags().String("name-match", "", "Only list the tasks whose name matches this glob, where * matches any text and ? any character.")
+	statusCmd.Flags().String("format", "text", "Output format, one of text, ndjson.")
+
+	Command.AddCommand(statusCmd)
+}
+
+// groupTask is the part of a task of a group that status describes.
+type groupTask struct {
+	TaskID string `json:"taskId"`
+	State  string `json:"state"`
+	Name   string `json:"name"`
+}
+
+// taskFilter selects the tasks to list.
+type taskFilter struct {
+	states map[string]bool
+	name   *regexp.Regexp
+}
+
+// parseTaskFilter builds the filter given by --filter and --name-match.
+func parseTaskFilter(flags *pflag.FlagSet) (*taskFilter, error) {
+	filter := &taskFilter{}
+	states, _ := flags.GetStringSlice("filter")
+	for _, state := range states {
+		state = strings.TrimSpace(state)
+		if !validState(state) {
+			return nil, fmt.Errorf("invalid state %q in --filter, expected one of %s", state, strings.Join(taskStates, ", "))
+		}
+		if filter.states == nil {
+			filter.states = make(map[string]bool)
+		}
+		filter.states[state] = true
+	}
+	if glob, _ := flags.GetString("name-match"); glob != "" {
+		filter.name = globRegexp(glob)
+	}
+	return filter, nil
+}
+
+// match tells whether task should be listed.
+func (f *taskFilter) match(task groupTask) bool {
+	if f.states != nil && !f.states[task.State] {
+		return false
+	}
+	return f.name == nil || f.name.MatchString(task.Name)
+}
+
+func validState(state string) bool {
+	for _, s := range taskStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// globRegexp compiles glob, in which * matches any text, including slashes as
+// they are common in task names, and ? matches any single character.
+func globRegexp(glob string) *regexp.Regexp {
+	var re bytes.Buffer
+	re.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			re.WriteString(".*")
+		case '?':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteString("$")
+	return regexp.MustCompile(re.String())
+}
+
+// runStatus summarizes the states of all tasks of a group, then lists the
+// tasks matching the filters.
+func runStatus(credentials *tcclient.Credentials, args []string, out io.Writer, flags *pflag.FlagSet) error {
+	filter, err := parseTaskFilter(flags)
+	if err != nil {
+		return err
+	}
+	switch format, _ := flags.GetString("format"); format {
+	case "text":
+	case "ndjson":
+		return streamGroupTasks(makeQueue(credentials), args[0], filter, out)
+	default:
+		return fmt.Errorf("invalid --format '%s', expected one of text, ndjson", format)
+	}
+
+	tasks, err := listGroupTasks(makeQueue(credentials), args[0])
+	if err != nil {
+		return err
+	}
+
+	printSummary(out, tasks)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, task := range tasks {
+		if filter.match(task) {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", task.TaskID, task.State, task.Name)
+		}
+	}
+	return w.Flush()
+}
+
+// streamGroupTasks writes the tasks of a group matching filter to out, as
+// one JSON object per line, page by page as they are fetched.
+func streamGroupTasks(q *queue.Queue, groupID string, filter *taskFilter, out io.Writer) error {
+	return eachGroupTask(q, groupID, func(task groupTask) error {
+		if !filter.match(task) {
+			return nil
+		}
+		data, err := json.Marshal(&task)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	})
+}
+
+// listGroupTasks fetches all the tasks of a group.
+func listGroupTasks(q *queue.Queue, groupID string) ([]groupTask, error) {
+	var tasks []groupTask
+	err := eachGroupTask(q, groupID, func(task groupTask) error {
+		tasks = append(tasks, task)
+		return nil
+	})
+	return tasks, err
+}
+
+// eachGroupTask calls f with every task of a group, as the pages of the group
+// are fetched, stopping at the first error.
+func eachGroupTask(q *queue.Queue, groupID string, f func(groupTask) error) error {
+	return client.Paginate(0, func(continuationToken string, _ int) (string, int, error) {
+		ts, err := q.ListTaskGroup(groupID, continuationToken, "")
+		if err != nil {
+			return "", 0, fmt.Errorf("could not fetch tasks for group %s: %v", groupID, err)
+		}
+		for _, t := range ts.Tasks {
+			err = f(groupTask{
+				TaskID: t.Status.TaskID,
+				State:  t.Status.State,
+				Name:   t.Task.Metadata.Name,
+			})
+			if err != nil {
+				return "", 0, err
+			}
+		}
+		return ts.ContinuationToken, len(ts.Tasks), nil
+	})
+}
+
+// printSummary writes the number of tasks of the group in each state, e.g.
+// "3 tasks: 2 completed, 1 failed".
+func printSummary(out io.Writer, tasks []groupTask) {
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		counts[task.State]++
+	}
+	var parts []string
+	for _, state := range taskStates {
+		if counts[state] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[state], state))
+		}
+	}
+	fmt.Fprintf(out, "%d tasks", len(tasks))
+	if len(parts) > 0 {
+		fmt.Fprintf(out, ": %s", strings.Join(parts, ", "))
+	}
+	fmt.Fprintln(out)
+}
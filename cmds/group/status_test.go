@@ -0,0 +1,93 @@
+package group
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+const fakeStatusGroupID = "Nf4ojQVGRYaTX0eVqcu2nA"
+
+// statusTaskGroupHandler lists a group of four tasks over two pages.
+func statusTaskGroupHandler(w http.ResponseWriter, r *http.Request) {
+	task := func(id, state, name string) string {
+		return fmt.Sprintf(`{"status": {"taskId": %q, "state": %q}, "task": {"metadata": {"name": %q}}}`, id, state, name)
+	}
+	if r.URL.Query().Get("continuationToken") == "" {
+		io.WriteString(w, `{"taskGroupId": "`+fakeStatusGroupID+`", "continuationToken": "next", "tasks": [`+
+			task("task1", "completed", "build linux64/opt")+","+
+			task("task2", "failed", "test linux64/opt")+"]}")
+		return
+	}
+	io.WriteString(w, `{"taskGroupId": "`+fakeStatusGroupID+`", "tasks": [`+
+		task("task3", "exception", "test win64/debug")+","+
+		task("task4", "completed", "lint")+"]}")
+}
+
+func (suite *FakeServerSuite) runStatus(flags ...string) (string, error) {
+	buf, cmd := setUpCommand()
+	cmd.Flags().StringSlice("filter", nil, "")
+	cmd.Flags().String("name-match", "", "")
+	cmd.Flags().String("format", "text", "")
+	if err := cmd.Flags().Parse(flags); err != nil {
+		return "", err
+	}
+	err := runStatus(&tcclient.Credentials{}, []string{fakeStatusGroupID}, cmd.OutOrStdout(), cmd.Flags())
+	return buf.String(), err
+}
+
+func (suite *FakeServerSuite) TestRunStatus() {
+	out, err := suite.runStatus()
+	suite.NoError(err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	suite.Equal("4 tasks: 2 completed, 1 failed, 1 exception", lines[0])
+	suite.Len(lines, 5)
+}
+
+func (suite *FakeServerSuite) TestRunStatusFilter() {
+	out, err := suite.runStatus("--filter", "failed,exception")
+	suite.NoError(err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	suite.Equal("4 tasks: 2 completed, 1 failed, 1 exception", lines[0], "the summary covers the whole group")
+	suite.Len(lines, 3)
+	suite.Contains(lines[1], "task2")
+	suite.Contains(lines[2], "task3")
+}
+
+func (suite *FakeServerSuite) TestRunStatusNameMatch() {
+	out, err := suite.runStatus("--filter", "completed", "--name-match", "*linux64/*")
+	suite.NoError(err)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	suite.Len(lines, 2)
+	suite.Contains(lines[1], "task1")
+}
+
+func (suite *FakeServerSuite) TestRunStatusInvalidFilter() {
+	_, err := suite.runStatus("--filter", "broken")
+	suite.Error(err)
+}
+
+func (suite *FakeServerSuite) TestRunStatusNDJSON() {
+	out, err := suite.runStatus("--format", "ndjson", "--filter", "failed,exception")
+	suite.NoError(err)
+	suite.Equal(`{"taskId":"task2","state":"failed","name":"test linux64/opt"}`+"\n"+
+		`{"taskId":"task3","state":"exception","name":"test win64/debug"}`+"\n", out)
+
+	_, err = suite.runStatus("--format", "yaml")
+	suite.Error(err)
+}
+
+func TestGlobRegexp(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(globRegexp("test *").MatchString("test linux64/opt"))
+	assert.True(globRegexp("lin?").MatchString("lint"))
+	assert.True(globRegexp("a.b").MatchString("a.b"))
+	assert.False(globRegexp("a.b").MatchString("axb"), "only * and ? are special")
+	assert.False(globRegexp("test").MatchString("test linux64"), "globs match whole names")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
 	"github.com/taskcluster/taskcluster-cli/config"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 )
@@ -23,6 +24,9 @@ func executeHelperE(f Executor) func(*cobra.Command, []string) error {
 		if len(args) < 1 {
 			return fmt.Errorf("%s expects argument <taskId>", cmd.Name())
 		}
+		if endpoint := client.Endpoint(cmd.Flags(), "queue"); endpoint != "" {
+			queueBaseURL = endpoint
+		}
 		return f(creds, args, cmd.OutOrStdout(), cmd.Flags())
 	}
 }
@@ -0,0 +1,156 @@
+package hook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/hooks"
+)
+
+type (
+	// lastFire holds the union of the fields of the three possible shapes of
+	// the lastFire property of a hook status (success, error and no-fire).
+	lastFire struct {
+		Result string          `json:"result"`
+		TaskID string          `json:"taskId,omitempty"`
+		Time   *tcclient.Time  `json:"time,omitempty"`
+		Error  json.RawMessage `json:"error,omitempty"`
+	}
+
+	// hookSummary is the information printed about each hook by `hook list`.
+	hookSummary struct {
+		HookGroupID       string         `json:"hookGroupId"`
+		HookID            string         `json:"hookId"`
+		Name              string         `json:"name"`
+		Schedule          []string       `json:"schedule"`
+		LastFire          *lastFire      `json:"lastFire,omitempty"`
+		NextScheduledDate *tcclient.Time `json:"nextScheduledDate,omitempty"`
+	}
+)
+
+// runList lists the hooks of a hook group along with their last fire.
+func runList(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
+	h := makeHooks(credentials)
+	hookGroupID := args[0]
+	asJSON, _ := flagSet.GetBool("json")
+	if len(client.Fields(flagSet)) > 0 && !asJSON {
+		return errors.New("--fields requires --json")
+	}
+	if err := client.ValidateFields(flagSet, hookSummary{}); err != nil {
+		return err
+	}
+
+	l, err := h.ListHooks(hookGroupID)
+	if err != nil {
+		return fmt.Errorf("could not list the hooks of group %s: %v", hookGroupID, err)
+	}
+	// counting the hooks doesn't need their status
+	if client.PrintCount(out, flagSet, len(l.Hooks)) {
+		return nil
+	}
+
+	summaries := make([]hookSummary, 0, len(l.Hooks))
+	for _, hook := range l.Hooks {
+		summary := hookSummary{
+			HookGroupID: hook.HookGroupID,
+			HookID:      hook.HookID,
+			Name:        hook.Metadata.Name,
+			Schedule:    []string{},
+		}
+		if len(hook.Schedule) > 0 {
+			if err := json.Unmarshal(hook.Schedule, &summary.Schedule); err != nil {
+				return fmt.Errorf("could not parse the schedule of hook %s/%s: %v", hook.HookGroupID, hook.HookID, err)
+			}
+		}
+
+		s, err := h.GetHookStatus(hook.HookGroupID, hook.HookID)
+		if err != nil {
+			return fmt.Errorf("could not get the status of hook %s/%s: %v", hook.HookGroupID, hook.HookID, err)
+		}
+		if summary.LastFire, err = parseLastFire(s); err != nil {
+			return fmt.Errorf("could not parse the status of hook %s/%s: %v", hook.HookGroupID, hook.HookID, err)
+		}
+		summary.NextScheduledDate = nextScheduledDate(s)
+
+		summaries = append(summaries, summary)
+	}
+
+	if asJSON {
+		return client.PrintJSON(out, flagSet, summaries)
+	}
+
+	for _, s := range summaries {
+		fmt.Fprintf(out, "%s: %s\n", s.HookID, s.Name)
+		if len(s.Schedule) > 0 {
+			fmt.Fprintf(out, "\tSchedule:  %s\n", strings.Join(s.Schedule, ", "))
+		} else {
+			fmt.Fprintln(out, "\tSchedule:  none")
+		}
+		fmt.Fprintf(out, "\tLast fire: %s\n", getLastFireString(s.LastFire))
+	}
+	return nil
+}
+
+// runStatus prints the result of the last fire of a hook.
+func runStatus(credentials *tcclient.Credentials, args []string, out io.Writer, _ *pflag.FlagSet) error {
+	h := makeHooks(credentials)
+	hookGroupID, hookID := args[0], args[1]
+
+	s, err := h.GetHookStatus(hookGroupID, hookID)
+	if err != nil {
+		return fmt.Errorf("could not get the status of hook %s/%s: %v", hookGroupID, hookID, err)
+	}
+	lf, err := parseLastFire(s)
+	if err != nil {
+		return fmt.Errorf("could not parse the status of hook %s/%s: %v", hookGroupID, hookID, err)
+	}
+
+	fmt.Fprintf(out, "Last fire: %s\n", getLastFireString(lf))
+	if next := nextScheduledDate(s); next != nil {
+		fmt.Fprintf(out, "Next fire: %s\n", next)
+	}
+	return nil
+}
+
+// parseLastFire extracts the last fire from a hook status, returning nil if
+// the hook status doesn't include one.
+func parseLastFire(s *hooks.HookStatusResponse) (*lastFire, error) {
+	if len(s.LastFire) == 0 {
+		return nil, nil
+	}
+	lf := &lastFire{}
+	if err := json.Unmarshal(s.LastFire, lf); err != nil {
+		return nil, err
+	}
+	return lf, nil
+}
+
+// nextScheduledDate returns the next scheduled date of a hook status, or nil
+// if the hook isn't scheduled.
+func nextScheduledDate(s *hooks.HookStatusResponse) *tcclient.Time {
+	if time.Time(s.NextScheduledDate).IsZero() {
+		return nil
+	}
+	return &s.NextScheduledDate
+}
+
+// getLastFireString crafts a printable summary string of a last fire.
+func getLastFireString(lf *lastFire) string {
+	switch {
+	case lf == nil:
+		return "unknown"
+	case lf.Result == "success":
+		return fmt.Sprintf("success (task %s created at %s)", lf.TaskID, lf.Time)
+	case lf.Result == "error":
+		return fmt.Sprintf("error at %s: %s", lf.Time, string(lf.Error))
+	default:
+		return lf.Result
+	}
+}
@@ -0,0 +1,138 @@
+package hook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+const fakeHookGroupID = "project-taskcluster"
+const fakeHookID = "nightly"
+
+type FakeServerSuite struct {
+	suite.Suite
+	testServer *httptest.Server
+}
+
+func (suite *FakeServerSuite) SetupSuite() {
+	// set up a fake server that knows how to answer the `listHooks()` and
+	// `getHookStatus()` methods
+	handler := http.NewServeMux()
+	handler.HandleFunc("/v1/hooks/"+fakeHookGroupID, listHooksHandler)
+	handler.HandleFunc("/v1/hooks/"+fakeHookGroupID+"/"+fakeHookID+"/status", hookStatusHandler)
+
+	suite.testServer = httptest.NewServer(handler)
+
+	// set the base URL the subcommands use to point to the fake server
+	hooksBaseURL = suite.testServer.URL + "/v1"
+}
+
+func (suite *FakeServerSuite) TearDownSuite() {
+	suite.testServer.Close()
+	hooksBaseURL = ""
+}
+
+func TestFakeServerSuite(t *testing.T) {
+	suite.Run(t, new(FakeServerSuite))
+}
+
+func listHooksHandler(w http.ResponseWriter, _ *http.Request) {
+	list := `{
+		"hooks": [
+			{
+				"hookGroupId": "project-taskcluster",
+				"hookId": "nightly",
+				"metadata": {
+					"name": "Nightly build",
+					"description": "Builds every night",
+					"owner": "name@example.com"
+				},
+				"schedule": ["0 0 0 * * *"],
+				"deadline": "1 day",
+				"expires": "3 months",
+				"task": {}
+			}
+		]
+	}`
+	io.WriteString(w, list)
+}
+
+func hookStatusHandler(w http.ResponseWriter, _ *http.Request) {
+	status := `{
+		"lastFire": {
+			"result": "success",
+			"taskId": "ANnmjMocTymeTID0tlNJAw",
+			"time": "2017-04-10T00:00:01.000Z"
+		},
+		"nextScheduledDate": "2017-04-11T00:00:00.000Z"
+	}`
+	io.WriteString(w, status)
+}
+
+func setUpCommand() (*bytes.Buffer, *cobra.Command) {
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().Bool("count", false, "")
+
+	return buf, cmd
+}
+
+func (suite *FakeServerSuite) TestListCommand() {
+	buf, cmd := setUpCommand()
+
+	args := []string{fakeHookGroupID}
+	suite.NoError(runList(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal(
+		"nightly: Nightly build\n"+
+			"\tSchedule:  0 0 0 * * *\n"+
+			"\tLast fire: success (task ANnmjMocTymeTID0tlNJAw created at 2017-04-10T00:00:01.000Z)\n",
+		buf.String(),
+	)
+}
+
+func (suite *FakeServerSuite) TestListCountCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("count", "true")
+
+	args := []string{fakeHookGroupID}
+	suite.NoError(runList(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("1\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestListJSONCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("json", "true")
+
+	args := []string{fakeHookGroupID}
+	suite.NoError(runList(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	var summaries []hookSummary
+	suite.NoError(json.Unmarshal(buf.Bytes(), &summaries))
+	suite.Len(summaries, 1)
+	suite.Equal([]string{"0 0 0 * * *"}, summaries[0].Schedule)
+	suite.Equal("success", summaries[0].LastFire.Result)
+}
+
+func (suite *FakeServerSuite) TestStatusCommand() {
+	buf, cmd := setUpCommand()
+
+	args := []string{fakeHookGroupID, fakeHookID}
+	suite.NoError(runStatus(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal(
+		"Last fire: success (task ANnmjMocTymeTID0tlNJAw created at 2017-04-10T00:00:01.000Z)\n"+
+			"Next fire: 2017-04-11T00:00:00.000Z\n",
+		buf.String(),
+	)
+}
@@ -0,0 +1,43 @@
+package hook
+
+import (
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Command is the root of the hook subtree.
+	Command = &cobra.Command{
+		Use:   "hook",
+		Short: "Provides hook-related actions and commands.",
+	}
+)
+
+func init() {
+	client.AddEndpointFlag(Command.PersistentFlags(), "hooks")
+
+	listCmd := &cobra.Command{
+		Use:   "list <hookGroupId>",
+		Short: "List the hooks of a hook group, with their schedule and last fire.",
+		RunE:  executeHelperE(runList, "<hookGroupId>"),
+	}
+	listCmd.Flags().Bool("json", false, "Output the hooks as JSON.")
+	client.AddCountFlag(listCmd.Flags())
+	client.AddFieldsFlag(listCmd.Flags(), "hookId,name")
+
+	Command.AddCommand(
+		// list
+		listCmd,
+		// status
+		&cobra.Command{
+			Use:   "status <hookGroupId> <hookId>",
+			Short: "Get the result of the last fire of a hook.",
+			RunE:  executeHelperE(runStatus, "<hookGroupId> <hookId>"),
+		},
+	)
+
+	// Add the hook subtree to the root.
+	root.Command.AddCommand(Command)
+}
@@ -0,0 +1,44 @@
+package hook
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
+	"github.com/taskcluster/taskcluster-cli/config"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/hooks"
+)
+
+// Executor represents the function interface of the hook subcommand.
+type Executor func(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error
+
+// allow overriding the base URL, with --endpoint or for testing
+var hooksBaseURL string
+
+func makeHooks(credentials *tcclient.Credentials) *hooks.Hooks {
+	return clientfactory.New(credentials).WithBaseURL("hooks", hooksBaseURL).Hooks()
+}
+
+// executeHelperE wraps f into a cobra RunE, checking that the arguments
+// described by usage (e.g. "<hookGroupId> <hookId>") are present.
+func executeHelperE(f Executor, usage string) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var creds *tcclient.Credentials
+		if config.Credentials != nil {
+			creds = config.Credentials.ToClientCredentials()
+		}
+
+		if len(args) < len(strings.Fields(usage)) {
+			return fmt.Errorf("%s expects argument(s) %s", cmd.Name(), usage)
+		}
+		if endpoint := client.Endpoint(cmd.Flags(), "hooks"); endpoint != "" {
+			hooksBaseURL = endpoint
+		}
+		return f(creds, args, cmd.OutOrStdout(), cmd.Flags())
+	}
+}
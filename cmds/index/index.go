@@ -0,0 +1,35 @@
+package index
+
+import (
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Command is the root of the index subtree.
+	Command = &cobra.Command{
+		Use:   "index",
+		Short: "Provides index-related actions and commands.",
+	}
+)
+
+func init() {
+	client.AddEndpointFlag(Command.PersistentFlags(), "index")
+
+	listNamespacesCmd := &cobra.Command{
+		Use:   "list-namespaces <namespace>",
+		Short: "List the namespaces immediately under a given namespace.",
+		RunE:  executeHelperE(runListNamespaces),
+	}
+	listNamespacesCmd.Flags().Bool("json", false, "Output the namespaces as JSON.")
+	listNamespacesCmd.Flags().Int("limit", 0, "Stop after listing this many namespaces; 0 lists them all.")
+	client.AddCountFlag(listNamespacesCmd.Flags())
+	client.AddFieldsFlag(listNamespacesCmd.Flags(), "namespace,expires")
+
+	Command.AddCommand(listNamespacesCmd)
+
+	// Add the index subtree to the root.
+	root.Command.AddCommand(Command)
+}
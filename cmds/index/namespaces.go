@@ -0,0 +1,71 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/index"
+)
+
+// maxPageSize is the maximum number of results per page accepted by the
+// index service.
+const maxPageSize = 1000
+
+// namespace is a single entry of the listNamespaces response.
+type namespace struct {
+	Expires   tcclient.Time `json:"expires"`
+	Name      string        `json:"name"`
+	Namespace string        `json:"namespace"`
+}
+
+// runListNamespaces lists the namespaces immediately under a given namespace.
+func runListNamespaces(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
+	i := makeIndex(credentials)
+	parent := args[0]
+	asJSON, _ := flagSet.GetBool("json")
+	if len(client.Fields(flagSet)) > 0 && !asJSON {
+		return errors.New("--fields requires --json")
+	}
+	if err := client.ValidateFields(flagSet, namespace{}); err != nil {
+		return err
+	}
+
+	// Because the list of namespaces can be arbitrarily long, we have to loop
+	// until we are told not to.
+	namespaces := make([]namespace, 0)
+	limit, _ := flagSet.GetInt("limit")
+	err := client.Paginate(limit, func(continuationToken string, pageLimit int) (string, int, error) {
+		ns, err := i.ListNamespaces(parent, &index.ListNamespacesRequest{
+			ContinuationToken: continuationToken,
+			Limit:             client.PageSize(maxPageSize, pageLimit),
+		})
+		if err != nil {
+			return "", 0, fmt.Errorf("could not list namespaces of %s: %v", parent, err)
+		}
+
+		page := ns.Namespaces[:client.PageSize(len(ns.Namespaces), pageLimit)]
+		for _, n := range page {
+			namespaces = append(namespaces, namespace(n))
+		}
+		return ns.ContinuationToken, len(page), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if client.PrintCount(out, flagSet, len(namespaces)) {
+		return nil
+	}
+	if asJSON {
+		return client.PrintJSON(out, flagSet, namespaces)
+	}
+
+	for _, n := range namespaces {
+		fmt.Fprintln(out, n.Namespace)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package index
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/config"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+const fakeNamespace = "project.taskcluster"
+
+type FakeServerSuite struct {
+	suite.Suite
+	testServer *httptest.Server
+}
+
+func (suite *FakeServerSuite) SetupSuite() {
+	// set up a fake server that knows how to answer the `listNamespaces()` method
+	handler := http.NewServeMux()
+	handler.HandleFunc("/v1/namespaces/"+fakeNamespace, listNamespacesHandler)
+
+	suite.testServer = httptest.NewServer(handler)
+
+	// set the base URL the subcommands use to point to the fake server
+	indexBaseURL = suite.testServer.URL + "/v1"
+}
+
+func (suite *FakeServerSuite) TearDownSuite() {
+	suite.testServer.Close()
+	indexBaseURL = ""
+}
+
+func TestFakeServerSuite(t *testing.T) {
+	suite.Run(t, new(FakeServerSuite))
+}
+
+// returns the namespaces in two pages, to exercise the continuation token
+func listNamespacesHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ContinuationToken string `json:"continuationToken"`
+	}
+	json.NewDecoder(r.Body).Decode(&payload)
+
+	if payload.ContinuationToken == "" {
+		io.WriteString(w, `{
+			"namespaces": [
+				{
+					"namespace": "project.taskcluster.cli",
+					"name": "cli",
+					"expires": "2018-03-30T15:49:31.389Z"
+				}
+			],
+			"continuationToken": "next-page"
+		}`)
+		return
+	}
+
+	io.WriteString(w, `{
+		"namespaces": [
+			{
+				"namespace": "project.taskcluster.queue",
+				"name": "queue",
+				"expires": "2018-03-30T15:49:31.389Z"
+			}
+		]
+	}`)
+}
+
+func setUpCommand() (*bytes.Buffer, *cobra.Command) {
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().Int("limit", 0, "")
+	cmd.Flags().Bool("count", false, "")
+	cmd.Flags().StringSlice("fields", nil, "")
+
+	return buf, cmd
+}
+
+func (suite *FakeServerSuite) TestListNamespacesCommand() {
+	// set up to run a command and capture output
+	buf, cmd := setUpCommand()
+
+	// run the command
+	args := []string{fakeNamespace}
+	suite.NoError(runListNamespaces(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("project.taskcluster.cli\nproject.taskcluster.queue\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestListNamespacesJSONCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("json", "true")
+
+	args := []string{fakeNamespace}
+	suite.NoError(runListNamespaces(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	var namespaces []namespace
+	suite.NoError(json.Unmarshal(buf.Bytes(), &namespaces))
+	suite.Len(namespaces, 2)
+	suite.Equal("queue", namespaces[1].Name)
+}
+
+func (suite *FakeServerSuite) TestListNamespacesFieldsCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("json", "true")
+	cmd.Flags().Set("fields", "name")
+
+	args := []string{fakeNamespace}
+	suite.NoError(runListNamespaces(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	var namespaces []map[string]interface{}
+	suite.NoError(json.Unmarshal(buf.Bytes(), &namespaces))
+	suite.Equal([]map[string]interface{}{{"name": "cli"}, {"name": "queue"}}, namespaces)
+}
+
+func (suite *FakeServerSuite) TestListNamespacesInvalidFieldsCommand() {
+	_, cmd := setUpCommand()
+	cmd.Flags().Set("fields", "name")
+
+	args := []string{fakeNamespace}
+	suite.Error(runListNamespaces(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()), "--fields requires --json")
+
+	cmd.Flags().Set("json", "true")
+	cmd.Flags().Set("fields", "nope")
+	suite.Error(runListNamespaces(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+}
+
+func (suite *FakeServerSuite) TestListNamespacesCountCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("json", "true")
+	cmd.Flags().Set("count", "true")
+
+	args := []string{fakeNamespace}
+	suite.NoError(runListNamespaces(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("2\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestListNamespacesLimitCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("limit", "1")
+
+	args := []string{fakeNamespace}
+	suite.NoError(runListNamespaces(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("project.taskcluster.cli\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestListNamespacesEndpointCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().String("endpoint", "", "")
+	cmd.Flags().Set("endpoint", suite.testServer.URL+"/v1")
+
+	// only --endpoint should point the command at the fake server
+	defer func(u string) { indexBaseURL = u }(indexBaseURL)
+	indexBaseURL = ""
+	defer func(c *client.Credentials) { config.Credentials = c }(config.Credentials)
+	config.Credentials = &client.Credentials{ClientID: "tester", AccessToken: "no-secret"}
+
+	args := []string{fakeNamespace}
+	suite.NoError(executeHelperE(runListNamespaces)(cmd, args))
+
+	suite.Equal("project.taskcluster.cli\nproject.taskcluster.queue\n", buf.String())
+}
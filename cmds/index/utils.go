@@ -0,0 +1,41 @@
+package index
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
+	"github.com/taskcluster/taskcluster-cli/config"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/index"
+)
+
+// Executor represents the function interface of the index subcommand.
+type Executor func(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error
+
+// allow overriding the base URL, with --endpoint or for testing
+var indexBaseURL string
+
+func makeIndex(credentials *tcclient.Credentials) *index.Index {
+	return clientfactory.New(credentials).WithBaseURL("index", indexBaseURL).Index()
+}
+
+func executeHelperE(f Executor) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var creds *tcclient.Credentials
+		if config.Credentials != nil {
+			creds = config.Credentials.ToClientCredentials()
+		}
+
+		if len(args) < 1 {
+			return fmt.Errorf("%s expects argument <namespace>", cmd.Name())
+		}
+		if endpoint := client.Endpoint(cmd.Flags(), "index"); endpoint != "" {
+			indexBaseURL = endpoint
+		}
+		return f(creds, args, cmd.OutOrStdout(), cmd.Flags())
+	}
+}
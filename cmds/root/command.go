@@ -1,6 +1,12 @@
 package root
 
-import "github.com/spf13/cobra"
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/config"
+)
 
 var (
 	// Command is the root of the command tree.
@@ -8,5 +14,57 @@ var (
 		Use:   "taskcluster",
 		Short: "TaskCluster CLI client.",
 		Long:  "A command-line interface to TaskCluster - see https://docs.taskcluster.net.",
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			// the transport must be set before --trace wraps it
+			if err := client.ConfigureTransport(cmd.Flags()); err != nil {
+				return err
+			}
+			if err := client.ConfigureJSON(cmd.Flags()); err != nil {
+				return err
+			}
+			if err := client.ConfigureColor(cmd.Flags()); err != nil {
+				return err
+			}
+			if trace, _ := cmd.Flags().GetBool("trace"); trace {
+				client.EnableTrace(os.Stderr)
+			}
+			if path, _ := cmd.Flags().GetString("credentials-file"); path != "" {
+				creds, err := config.LoadCredentialsFile(path)
+				if err != nil {
+					return err
+				}
+				config.Credentials = creds
+				config.CredentialsSource = "--credentials-file " + path
+			}
+			for _, hook := range PreRunHooks {
+				hook(cmd)
+			}
+			return nil
+		},
+		PersistentPostRun: func(cmd *cobra.Command, _ []string) {
+			for _, hook := range PostRunHooks {
+				hook(cmd)
+			}
+		},
 	}
+
+	// PreRunHooks are run before every command, once the flags of the root
+	// are handled. They let packages which the root can't import, as they
+	// import it, act on every command; they register them from init.
+	PreRunHooks []func(cmd *cobra.Command)
+	// PostRunHooks are run after every command which succeeded.
+	PostRunHooks []func(cmd *cobra.Command)
 )
+
+func init() {
+	client.AddTransportFlags(Command.PersistentFlags())
+	client.AddJSONFlags(Command.PersistentFlags())
+	client.AddColorFlag(Command.PersistentFlags())
+	Command.PersistentFlags().Bool("trace", false, "Write every HTTP request and response to stderr, with credentials redacted.")
+	Command.PersistentFlags().String("credentials-file", "", "Load the credentials from this JSON file, with clientId, accessToken and optionally certificate; "+
+		"overrides the "+config.CredentialsFileEnvVar+" environment variable and the configured credentials.")
+	Command.PersistentFlags().Bool("errors-to-stdout", false, "With JSON output, write errors to stdout rather than stderr.")
+	Command.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &UsageError{Err: err}
+	})
+}
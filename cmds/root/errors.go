@@ -0,0 +1,125 @@
+package root
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Codes of errors, as output with JSON errors, besides those given with
+// WithCode.
+const (
+	// ErrorCodeUsage is the code of mistakes in the invocation of a
+	// command, such as an unknown flag.
+	ErrorCodeUsage = "usage"
+	// ErrorCodeFailure is the code of all other errors.
+	ErrorCodeFailure = "failure"
+)
+
+// UsageError is a mistake in the invocation of a command, such as an unknown
+// flag or an invalid argument.
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+// CodedError is an error along with a code identifying its kind, such as
+// "unhealthy", for automation to tell errors apart.
+type CodedError struct {
+	Code string
+	Err  error
+}
+
+func (e *CodedError) Error() string {
+	return e.Err.Error()
+}
+
+// WithCode returns err along with code, the identifier of its kind output with
+// JSON errors.
+func WithCode(code string, err error) error {
+	return &CodedError{Code: code, Err: err}
+}
+
+// ErrorCode returns the code identifying the kind of err: that given with
+// WithCode, ErrorCodeUsage for usage errors, or ErrorCodeFailure.
+func ErrorCode(err error) string {
+	switch e := err.(type) {
+	case *CodedError:
+		return e.Code
+	case *UsageError:
+		return ErrorCodeUsage
+	case *ExitError:
+		if e.Err != nil {
+			return ErrorCode(e.Err)
+		}
+	}
+	if strings.HasPrefix(err.Error(), "unknown command") {
+		// cobra doesn't tell its errors apart
+		return ErrorCodeUsage
+	}
+	return ErrorCodeFailure
+}
+
+// jsonError is the structure errors are output in by JSON commands.
+type jsonError struct {
+	Error struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// jsonOutput tells whether cmd was asked for JSON output, with --json or
+// --format json.
+func jsonOutput(cmd *cobra.Command) bool {
+	if asJSON, err := cmd.Flags().GetBool("json"); err == nil && asJSON {
+		return true
+	}
+	format, err := cmd.Flags().GetString("format")
+	return err == nil && (format == "json" || format == "ndjson")
+}
+
+// Execute runs the command given on the command line, like Command.Execute,
+// and reports its error, if any. Commands with JSON output report errors as a
+// JSON object, {"error": {"message": ..., "code": ...}}, to stderr, or stdout
+// with --errors-to-stdout; others report them as text to stderr.
+func Execute() error {
+	// errors are reported here, set on the root for cobra to leave them be
+	Command.SilenceErrors, Command.SilenceUsage = true, true
+	cmd, err := Command.ExecuteC()
+	Command.SilenceErrors, Command.SilenceUsage = false, false
+	if err == nil || cmd == nil || cmd.SilenceErrors {
+		return err
+	}
+	if jsonOutput(cmd) {
+		out := cmd.OutOrStderr()
+		if toStdout, _ := cmd.Flags().GetBool("errors-to-stdout"); toStdout {
+			out = cmd.OutOrStdout()
+		}
+		writeJSONError(out, err)
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStderr(), "Error:", err.Error())
+	switch {
+	case strings.HasPrefix(err.Error(), "unknown command"):
+		fmt.Fprintf(cmd.OutOrStderr(), "Run '%v --help' for usage.\n", cmd.CommandPath())
+	case !cmd.SilenceUsage:
+		fmt.Fprintln(cmd.OutOrStderr(), cmd.UsageString())
+	}
+	return err
+}
+
+// writeJSONError writes err to out as a JSON object.
+func writeJSONError(out io.Writer, err error) {
+	var e jsonError
+	e.Error.Message = err.Error()
+	e.Error.Code = ErrorCode(err)
+	data, _ := json.Marshal(&e)
+	fmt.Fprintln(out, string(data))
+}
@@ -0,0 +1,70 @@
+package root
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestErrorCode(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(ErrorCodeFailure, ErrorCode(errors.New("boom")))
+	assert.Equal(ErrorCodeUsage, ErrorCode(&UsageError{Err: errors.New("unknown flag: --nope")}))
+	assert.Equal(ErrorCodeUsage, ErrorCode(errors.New(`unknown command "nope" for "taskcluster"`)))
+	assert.Equal("unhealthy", ErrorCode(WithCode("unhealthy", errors.New("queue is down"))))
+	assert.Equal("unhealthy", ErrorCode(&ExitError{Code: 7, Err: WithCode("unhealthy", errors.New("queue is down"))}))
+	assert.Equal(ErrorCodeFailure, ErrorCode(&ExitError{Code: 7}))
+	assert.Equal("queue is down", WithCode("unhealthy", errors.New("queue is down")).Error())
+}
+
+func TestWriteJSONError(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	writeJSONError(buf, &ExitError{Code: 2, Err: &UsageError{Err: errors.New(`bad "flag"`)}})
+	assert.Equal(`{"error":{"message":"bad \"flag\"","code":"usage"}}`+"\n", buf.String())
+}
+
+// executeFailing runs a command failing with err through Execute, with args,
+// and returns its output.
+func executeFailing(t *testing.T, err error, args ...string) string {
+	cmd := &cobra.Command{
+		Use: "failing",
+		RunE: func(*cobra.Command, []string) error {
+			return err
+		},
+	}
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().String("format", "", "")
+	Command.AddCommand(cmd)
+	defer Command.RemoveCommand(cmd)
+
+	buf := &bytes.Buffer{}
+	Command.SetOutput(buf)
+	defer Command.SetOutput(nil)
+	Command.SetArgs(append([]string{"failing"}, args...))
+	defer Command.SetArgs(nil)
+
+	assert.Equal(t, err, Execute())
+	return buf.String()
+}
+
+func TestExecuteJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	err := WithCode("unhealthy", errors.New("queue is down"))
+	assert.Equal(`{"error":{"message":"queue is down","code":"unhealthy"}}`+"\n", executeFailing(t, err, "--json"))
+	assert.Equal(`{"error":{"message":"queue is down","code":"unhealthy"}}`+"\n", executeFailing(t, err, "--format", "json"))
+}
+
+func TestExecuteText(t *testing.T) {
+	assert := assert.New(t)
+
+	out := executeFailing(t, errors.New("queue is down"))
+	assert.Contains(out, "Error: queue is down\n")
+	assert.Contains(out, "Usage:")
+}
@@ -0,0 +1,27 @@
+package root
+
+// ExitError is an error which makes the process exit with Code, instead of
+// the exit code of 1 used for any other error.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return ""
+	}
+	return e.Err.Error()
+}
+
+// ExitCode returns the code the process should exit with after a command
+// returned err.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if e, ok := err.(*ExitError); ok {
+		return e.Code
+	}
+	return 1
+}
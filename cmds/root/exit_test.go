@@ -0,0 +1,17 @@
+package root
+
+import (
+	"errors"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(0, ExitCode(nil))
+	assert.Equal(1, ExitCode(errors.New("failed")))
+	assert.Equal(7, ExitCode(&ExitError{Code: 7, Err: errors.New("unhealthy")}))
+	assert.Equal("unhealthy", (&ExitError{Code: 7, Err: errors.New("unhealthy")}).Error())
+}
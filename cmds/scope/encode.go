@@ -0,0 +1,80 @@
+package scope
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Command.AddCommand(
+		&cobra.Command{
+			Use:   "encode [<scope>...]",
+			Short: "URL-encode scopes, to embed them in routes or query strings.",
+			Long: `URL-encode scopes, to embed them in routes or query strings, and print
+one per line. Everything but letters, digits and -_.~ is percent-encoded,
+including slashes and spaces, like JavaScript's encodeURIComponent.
+
+The scopes are read from the arguments, or from stdin, one per line.`,
+			RunE: encode,
+		},
+		&cobra.Command{
+			Use:   "decode [<encoded-scope>...]",
+			Short: "Decode URL-encoded scopes.",
+			Long: `Decode URL-encoded scopes and print one per line.
+
+The scopes are read from the arguments, or from stdin, one per line.`,
+			RunE: decode,
+		},
+	)
+}
+
+func encode(cmd *cobra.Command, args []string) error {
+	scopes, err := readScopes(args)
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		fmt.Fprintln(cmd.OutOrStdout(), encodeScope(scope))
+	}
+	return nil
+}
+
+func decode(cmd *cobra.Command, args []string) error {
+	scopes, err := readScopes(args)
+	if err != nil {
+		return err
+	}
+	// decode them all before printing any, so that failures don't give
+	// partial output
+	decoded := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		d, err := decodeScope(scope)
+		if err != nil {
+			return err
+		}
+		decoded = append(decoded, d)
+	}
+	for _, scope := range decoded {
+		fmt.Fprintln(cmd.OutOrStdout(), scope)
+	}
+	return nil
+}
+
+// encodeScope percent-encodes scope so that it can be used as a path segment
+// or a query string value.
+func encodeScope(scope string) string {
+	// QueryEscape encodes spaces as '+', which only query strings understand
+	return strings.Replace(url.QueryEscape(scope), "+", "%20", -1)
+}
+
+// decodeScope reverses encodeScope.
+func decodeScope(encoded string) (string, error) {
+	scope, err := url.PathUnescape(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid encoded scope %q: %v", encoded, err)
+	}
+	return scope, nil
+}
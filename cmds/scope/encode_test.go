@@ -0,0 +1,75 @@
+package scope
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+)
+
+func setUpCommand() (*bytes.Buffer, *cobra.Command) {
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+
+	return buf, cmd
+}
+
+func TestEncodeScope(t *testing.T) {
+	assert := assert.New(t)
+
+	for scope, encoded := range map[string]string{
+		"queue:create-task:aws-provisioner-v1/*": "queue%3Acreate-task%3Aaws-provisioner-v1%2F%2A",
+		"assume:project:a b":                     "assume%3Aproject%3Aa%20b",
+		"secrets:get:garbage/1+1":                "secrets%3Aget%3Agarbage%2F1%2B1",
+		"plain-scope_1.0~":                       "plain-scope_1.0~",
+	} {
+		assert.Equal(encoded, encodeScope(scope), scope)
+		decoded, err := decodeScope(encoded)
+		assert.NoError(err)
+		assert.Equal(scope, decoded, "decoding should reverse encoding")
+	}
+
+	_, err := decodeScope("queue%3")
+	assert.Error(err)
+}
+
+func TestEncodeArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	buf, cmd := setUpCommand()
+	assert.NoError(encode(cmd, []string{"a:b", "c/d"}))
+	assert.Equal("a%3Ab\nc%2Fd\n", buf.String())
+}
+
+func TestDecodeStdin(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("a%3Ab\n\nc%2Fd\r\n")
+
+	buf, cmd := setUpCommand()
+	assert.NoError(decode(cmd, nil))
+	assert.Equal("a:b\nc/d\n", buf.String())
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	buf, cmd := setUpCommand()
+	assert.Error(decode(cmd, []string{"a%3Ab", "%zz"}))
+	assert.Equal("", buf.String(), "nothing should be printed on failure")
+}
+
+func TestReadScopesEmpty(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("\n  \n")
+
+	_, err := readScopes(nil)
+	assert.Error(err)
+}
@@ -0,0 +1,166 @@
+package scope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/httpbackoff"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+	"github.com/taskcluster/taskcluster-cli/scopes"
+	"github.com/taskcluster/taskcluster-cli/timeparse"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/auth"
+)
+
+var (
+	// allow overriding the base URL, with --endpoint or for testing
+	authBaseURL string
+
+	// warnings is where disagreements between the local and remote checks
+	// are reported; it is a variable so tests can replace it.
+	warnings io.Writer = os.Stderr
+)
+
+// testerCredentials are those the auth service's testAuthenticate accepts in
+// place of real ones, with the client scopes given in the request.
+var testerCredentials = &tcclient.Credentials{
+	ClientID:    "tester",
+	AccessToken: "no-secret",
+}
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "match --have <scope>... [<scope>...]",
+		Short: "Check whether scopes are satisfied by a set of scopes.",
+		Long: `Check whether each of the given scopes is satisfied by the scopes given with
+--have, and print whether it is, one per line. The command fails if any scope
+isn't satisfied.
+
+A scope ending with a '*' satisfies every scope starting with what precedes
+the '*'; any other scope only satisfies itself. Roles are not expanded.
+
+With --remote, the auth service decides instead, expanding the roles assumed
+by the --have scopes; the local check is still made, and any disagreement is
+reported to stderr.
+
+The scopes are read from the arguments, or from stdin, one per line.`,
+		RunE: match,
+	}
+	cmd.Flags().StringArray("have", nil, "A scope held, to check the scopes against (repeatable).")
+	cmd.Flags().Bool("remote", false, "Have the auth service check the scopes, expanding roles, instead of the local check.")
+	timeparse.AddDurationFlag(cmd.Flags(), "timeout", time.Minute, "Give up on the auth service after this long, with --remote.")
+	client.AddEndpointFlag(cmd.Flags(), "auth")
+
+	Command.AddCommand(cmd)
+}
+
+func makeAuth(ctx context.Context) *auth.Auth {
+	f := clientfactory.New(testerCredentials).WithBaseURL("auth", authBaseURL)
+	f.HTTPClient = &client.ContextClient{Context: ctx, Client: client.HTTPClient}
+	return f.Auth()
+}
+
+func match(cmd *cobra.Command, args []string) error {
+	have, _ := cmd.Flags().GetStringArray("have")
+	if len(have) == 0 {
+		return errors.New("match requires the scopes held, with --have")
+	}
+	required, err := readScopes(args)
+	if err != nil {
+		return err
+	}
+
+	satisfied := make([]bool, len(required))
+	for i, scope := range required {
+		satisfied[i] = satisfiedLocally(have, scope)
+	}
+
+	if remote, _ := cmd.Flags().GetBool("remote"); remote {
+		if endpoint := client.Endpoint(cmd.Flags(), "auth"); endpoint != "" {
+			authBaseURL = endpoint
+		}
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		if timeout <= 0 {
+			return fmt.Errorf("--timeout must be positive, got %v", timeout)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		ctx, stop := client.InterruptContext(ctx)
+		defer stop()
+
+		for i, scope := range required {
+			ok, err := satisfiedRemotely(ctx, have, scope)
+			if err != nil {
+				return err
+			}
+			switch {
+			case ok && !satisfied[i]:
+				fmt.Fprintf(warnings, "warning: %s is satisfied according to the auth service, but not locally\n", scope)
+			case !ok && satisfied[i]:
+				fmt.Fprintf(warnings, "warning: %s is satisfied locally, but not according to the auth service\n", scope)
+			}
+			satisfied[i] = ok
+		}
+	}
+
+	tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+	missing := 0
+	for i, scope := range required {
+		state := "satisfied"
+		if !satisfied[i] {
+			state = "missing"
+			missing++
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", state, scope)
+	}
+	tw.Flush()
+
+	if missing > 0 {
+		cmd.SilenceUsage = true
+		return &root.ExitError{Code: 1, Err: root.WithCode("unsatisfied", fmt.Errorf("%d of %d scope(s) not satisfied", missing, len(required)))}
+	}
+	return nil
+}
+
+// satisfiedLocally tells whether scope is satisfied by one of have, without
+// expanding roles.
+func satisfiedLocally(have []string, scope string) bool {
+	return scopes.SatisfyingSet(have, []string{scope})
+}
+
+// satisfiedRemotely tells whether scope is satisfied by have, according to
+// the auth service, which expands the roles have assumes. It gives up as
+// soon as ctx is done.
+func satisfiedRemotely(ctx context.Context, have []string, scope string) (bool, error) {
+	a := makeAuth(ctx)
+	err := client.CallWithContext(ctx, func() error {
+		_, err := a.TestAuthenticate(&auth.TestAuthenticateRequest{
+			ClientScopes:   have,
+			RequiredScopes: []string{scope},
+		})
+		return err
+	})
+	switch e := err.(type) {
+	case nil:
+		return true, nil
+	case httpbackoff.BadHttpResponseCode:
+		if e.HttpResponseCode == 403 {
+			return false, nil
+		}
+	}
+	switch err {
+	case context.DeadlineExceeded:
+		return false, errors.New("timed out waiting for the auth service to check the scopes, see --timeout")
+	case context.Canceled:
+		return false, errors.New("interrupted while checking the scopes")
+	}
+	return false, fmt.Errorf("could not check the scopes with the auth service: %v", err)
+}
@@ -0,0 +1,115 @@
+package scope
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+)
+
+// testAuthenticateHandler knows a single role, project:admin, which grants
+// every scope.
+func testAuthenticateHandler(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ClientScopes   []string `json:"clientScopes"`
+		RequiredScopes []string `json:"requiredScopes"`
+	}
+	json.NewDecoder(r.Body).Decode(&payload)
+
+	have := payload.ClientScopes
+	for _, scope := range payload.ClientScopes {
+		if scope == "assume:project:admin" {
+			have = append(have, "*")
+		}
+	}
+	for _, scope := range payload.RequiredScopes {
+		if !satisfiedLocally(have, scope) {
+			w.WriteHeader(http.StatusForbidden)
+			io.WriteString(w, `{"code": "InsufficientScopes", "message": "nope"}`)
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `{"clientId": "tester", "scopes": []}`)
+}
+
+func setUpMatchCommand(have ...string) (*bytes.Buffer, *cobra.Command) {
+	buf, cmd := setUpCommand()
+	cmd.Flags().StringArray("have", have, "")
+	cmd.Flags().Bool("remote", false, "")
+	cmd.Flags().Duration("timeout", time.Minute, "")
+	return buf, cmd
+}
+
+func TestSatisfiedLocally(t *testing.T) {
+	assert := assert.New(t)
+
+	have := []string{"queue:create-task:aws-provisioner-v1/*", "secrets:get:garbage"}
+	assert.True(satisfiedLocally(have, "queue:create-task:aws-provisioner-v1/tutorial"))
+	assert.True(satisfiedLocally(have, "queue:create-task:aws-provisioner-v1/"))
+	assert.True(satisfiedLocally(have, "queue:create-task:aws-provisioner-v1/*"))
+	assert.True(satisfiedLocally(have, "secrets:get:garbage"))
+	assert.False(satisfiedLocally(have, "secrets:get:garbage/more"))
+	assert.False(satisfiedLocally(have, "queue:create-task:aws-provisioner-v1"))
+	assert.True(satisfiedLocally([]string{"*"}, "anything"))
+}
+
+func TestMatch(t *testing.T) {
+	assert := assert.New(t)
+
+	buf, cmd := setUpMatchCommand("queue:*")
+	assert.NoError(match(cmd, []string{"queue:create-task:foo", "queue:"}))
+	assert.Equal("satisfied  queue:create-task:foo\nsatisfied  queue:\n", buf.String())
+}
+
+func TestMatchMissing(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("queue:create-task:foo\nsecrets:get:garbage\n")
+
+	buf, cmd := setUpMatchCommand("queue:*")
+	err := match(cmd, nil)
+	assert.Error(err)
+	assert.Equal(1, root.ExitCode(err))
+	assert.Equal("unsatisfied", root.ErrorCode(err))
+	assert.Equal("satisfied  queue:create-task:foo\nmissing    secrets:get:garbage\n", buf.String())
+}
+
+func TestMatchWithoutHave(t *testing.T) {
+	_, cmd := setUpMatchCommand()
+	assert.Error(t, match(cmd, []string{"queue:*"}))
+}
+
+func TestMatchRemote(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(testAuthenticateHandler))
+	defer server.Close()
+	defer func(u string) { authBaseURL = u }(authBaseURL)
+	authBaseURL = server.URL
+	defer func(w io.Writer) { warnings = w }(warnings)
+	warned := &bytes.Buffer{}
+	warnings = warned
+
+	buf, cmd := setUpMatchCommand("assume:project:admin", "queue:*")
+	cmd.Flags().Set("remote", "true")
+	assert.NoError(match(cmd, []string{"queue:create-task:foo", "secrets:get:garbage"}))
+	assert.Equal("satisfied  queue:create-task:foo\nsatisfied  secrets:get:garbage\n", buf.String())
+	assert.Equal("warning: secrets:get:garbage is satisfied according to the auth service, but not locally\n", warned.String())
+
+	buf, cmd = setUpMatchCommand("queue:*")
+	cmd.Flags().Set("remote", "true")
+	warned.Reset()
+	assert.Error(match(cmd, []string{"queue:create-task:foo", "secrets:get:garbage"}))
+	assert.Equal("satisfied  queue:create-task:foo\nmissing    secrets:get:garbage\n", buf.String())
+	assert.Empty(warned.String())
+}
@@ -0,0 +1,58 @@
+package scope
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+)
+
+var (
+	// Command is the root of the scope subtree.
+	Command = &cobra.Command{
+		Use:   "scope",
+		Short: "Local utilities to work with scopes.",
+	}
+
+	// stdin is where scopes are read from when none are given as arguments;
+	// it is a variable so tests can replace it.
+	stdin io.Reader = os.Stdin
+)
+
+func init() {
+	root.Command.AddCommand(Command)
+}
+
+// readScopes returns the scopes given as args or, if there are none, those
+// read from stdin, one per line. Blank lines are ignored.
+func readScopes(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	scopes, err := scanScopes(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scopes from stdin, error: %s", err)
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("expected scopes as arguments or on stdin, one per line")
+	}
+	return scopes, nil
+}
+
+// scanScopes returns the scopes read from r, one per line, ignoring blank
+// lines.
+func scanScopes(r io.Reader) ([]string, error) {
+	var scopes []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); strings.TrimSpace(line) != "" {
+			scopes = append(scopes, line)
+		}
+	}
+	return scopes, scanner.Err()
+}
@@ -0,0 +1,85 @@
+package scope
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/scopes"
+)
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "sort [<scope>...]",
+		Short: "Sort scopes and remove duplicates.",
+		Long: `Sort scopes, remove duplicates, and print them one per line, to keep lists of
+scopes tidy, e.g. from a pre-commit hook. No network call is made, and roles
+are not expanded, unlike expand-scope.
+
+With --normalize, the scopes satisfied by another scope ending with a '*' are
+removed too.
+
+The scopes are read from the arguments, or from the file given with --file,
+or from stdin, one per line.`,
+		RunE: sortScopes,
+	}
+	cmd.Flags().StringP("file", "f", "", "Read the scopes from this file, one per line, instead of stdin.")
+	cmd.Flags().Bool("normalize", false, "Also remove the scopes satisfied by another scope ending with a '*'.")
+
+	Command.AddCommand(cmd)
+}
+
+func sortScopes(cmd *cobra.Command, args []string) error {
+	input, err := inputScopes(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	var sorted []string
+	if normalize, _ := cmd.Flags().GetBool("normalize"); normalize {
+		sorted = scopes.Normalize(input)
+	} else {
+		sorted = dedupe(input)
+		sort.Strings(sorted)
+	}
+	for _, scope := range sorted {
+		fmt.Fprintln(cmd.OutOrStdout(), scope)
+	}
+	return nil
+}
+
+// inputScopes returns the scopes given as args, or read from --file, or
+// from stdin.
+func inputScopes(cmd *cobra.Command, args []string) ([]string, error) {
+	path, _ := cmd.Flags().GetString("file")
+	if path == "" {
+		return readScopes(args)
+	}
+	if len(args) > 0 {
+		return nil, fmt.Errorf("scopes can't be given both as arguments and with --file")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scopes from %s, error: %s", path, err)
+	}
+	defer file.Close()
+	scopes, err := scanScopes(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scopes from %s, error: %s", path, err)
+	}
+	return scopes, nil
+}
+
+// dedupe returns scopes without duplicates, in their original order.
+func dedupe(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := []string{}
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,63 @@
+package scope
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSortScopes(t *testing.T) {
+	assert := assert.New(t)
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().String("file", "", "")
+	cmd.Flags().Bool("normalize", false, "")
+
+	assert.NoError(sortScopes(cmd, []string{"queue:b", "queue:*", "auth:a", "queue:b"}))
+	assert.Equal("auth:a\nqueue:*\nqueue:b\n", buf.String())
+
+	buf.Reset()
+	cmd.Flags().Set("normalize", "true")
+	assert.NoError(sortScopes(cmd, []string{"queue:b", "queue:*", "auth:a", "queue:b"}))
+	assert.Equal("auth:a\nqueue:*\n", buf.String())
+}
+
+func TestSortScopesStdin(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(r io.Reader) { stdin = r }(stdin)
+	stdin = strings.NewReader("queue:b\n\nqueue:a\r\n")
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().String("file", "", "")
+	cmd.Flags().Bool("normalize", false, "")
+
+	assert.NoError(sortScopes(cmd, nil))
+	assert.Equal("queue:a\nqueue:b\n", buf.String())
+}
+
+func TestSortScopesFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "scopes.txt")
+	assert.NoError(ioutil.WriteFile(path, []byte("queue:b\nqueue:a\nqueue:b\n"), 0644))
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().String("file", path, "")
+	cmd.Flags().Bool("normalize", false, "")
+
+	assert.NoError(sortScopes(cmd, nil))
+	assert.Equal("queue:a\nqueue:b\n", buf.String())
+	assert.Error(sortScopes(cmd, []string{"queue:c"}), "--file and arguments are exclusive")
+
+	cmd.Flags().Set("file", filepath.Join(dir, "missing.txt"))
+	assert.Error(sortScopes(cmd, nil))
+}
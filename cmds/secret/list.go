@@ -0,0 +1,76 @@
+package secret
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/secrets"
+)
+
+// secretsPage is a page of the response of the list endpoint. The vendored
+// client doesn't know about pagination, so pages are requested directly.
+type secretsPage struct {
+	Secrets           []string `json:"secrets"`
+	ContinuationToken string   `json:"continuationToken"`
+}
+
+// runList lists the names of the secrets the caller can read, never their
+// values.
+func runList(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
+	s := makeSecrets(credentials)
+
+	// Because the list of secrets can be arbitrarily long, we have to loop
+	// until we are told not to.
+	names := make([]string, 0)
+	limit, _ := flagSet.GetInt("limit")
+	err := client.Paginate(limit, func(continuationToken string, pageLimit int) (string, int, error) {
+		page, err := listSecrets(s, continuationToken, pageLimit)
+		if err != nil {
+			return "", 0, fmt.Errorf("could not list secrets: %v", err)
+		}
+
+		page.Secrets = page.Secrets[:client.PageSize(len(page.Secrets), pageLimit)]
+		names = append(names, page.Secrets...)
+		return page.ContinuationToken, len(page.Secrets), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if client.PrintCount(out, flagSet, len(names)) {
+		return nil
+	}
+	if asJSON, _ := flagSet.GetBool("json"); asJSON {
+		data, err := client.MarshalJSON(names)
+		if err != nil {
+			return fmt.Errorf("could not marshal secrets: %v", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	for _, name := range names {
+		fmt.Fprintln(out, name)
+	}
+	return nil
+}
+
+// listSecrets requests a page of the names of secrets, of at most limit names
+// if it is positive.
+func listSecrets(s *secrets.Secrets, continuationToken string, limit int) (*secretsPage, error) {
+	query := url.Values{}
+	if continuationToken != "" {
+		query.Set("continuationToken", continuationToken)
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	cd := tcclient.Client(*s)
+	page := new(secretsPage)
+	_, _, err := (&cd).APICall(nil, "GET", "/secrets", page, query)
+	return page, err
+}
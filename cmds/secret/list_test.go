@@ -0,0 +1,96 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/suite"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+type FakeServerSuite struct {
+	suite.Suite
+	testServer *httptest.Server
+}
+
+func (suite *FakeServerSuite) SetupSuite() {
+	// set up a fake server that knows how to answer the `list()` method
+	handler := http.NewServeMux()
+	handler.HandleFunc("/v1/secrets", listSecretsHandler)
+
+	suite.testServer = httptest.NewServer(handler)
+
+	// set the base URL the subcommands use to point to the fake server
+	secretsBaseURL = suite.testServer.URL + "/v1"
+}
+
+func (suite *FakeServerSuite) TearDownSuite() {
+	suite.testServer.Close()
+	secretsBaseURL = ""
+}
+
+func TestFakeServerSuite(t *testing.T) {
+	suite.Run(t, new(FakeServerSuite))
+}
+
+// returns the secrets in two pages, to exercise the continuation token
+func listSecretsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("continuationToken") == "" {
+		io.WriteString(w, `{"secrets": ["project/cli/a", "project/cli/b"], "continuationToken": "next-page"}`)
+		return
+	}
+	io.WriteString(w, `{"secrets": ["project/cli/c"]}`)
+}
+
+func setUpCommand() (*bytes.Buffer, *cobra.Command) {
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().Int("limit", 0, "")
+	cmd.Flags().Bool("count", false, "")
+
+	return buf, cmd
+}
+
+func (suite *FakeServerSuite) TestListCommand() {
+	buf, cmd := setUpCommand()
+
+	suite.NoError(runList(&tcclient.Credentials{}, nil, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("project/cli/a\nproject/cli/b\nproject/cli/c\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestListJSONCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("json", "true")
+
+	suite.NoError(runList(&tcclient.Credentials{}, nil, cmd.OutOrStdout(), cmd.Flags()))
+
+	var names []string
+	suite.NoError(json.Unmarshal(buf.Bytes(), &names))
+	suite.Equal([]string{"project/cli/a", "project/cli/b", "project/cli/c"}, names)
+}
+
+func (suite *FakeServerSuite) TestListLimitCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("limit", "1")
+
+	suite.NoError(runList(&tcclient.Credentials{}, nil, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("project/cli/a\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestListCountCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Set("count", "true")
+
+	suite.NoError(runList(&tcclient.Credentials{}, nil, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("3\n", buf.String())
+}
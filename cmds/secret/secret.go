@@ -0,0 +1,36 @@
+package secret
+
+import (
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Command is the root of the secret subtree.
+	Command = &cobra.Command{
+		Use:   "secret",
+		Short: "Provides secret-related actions and commands.",
+	}
+)
+
+func init() {
+	client.AddEndpointFlag(Command.PersistentFlags(), "secrets")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the names of the secrets you are allowed to read.",
+		Long: `List the names of the secrets you are allowed to read, that is those for
+which you have the scope secrets:get:<name>. Their values are never printed.`,
+		RunE: executeHelperE(runList),
+	}
+	listCmd.Flags().Bool("json", false, "Output the names of the secrets as a JSON array.")
+	listCmd.Flags().Int("limit", 0, "Stop after listing this many secrets; 0 lists them all.")
+	client.AddCountFlag(listCmd.Flags())
+
+	Command.AddCommand(listCmd)
+
+	// Add the secret subtree to the root.
+	root.Command.AddCommand(Command)
+}
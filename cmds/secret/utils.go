@@ -0,0 +1,37 @@
+package secret
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
+	"github.com/taskcluster/taskcluster-cli/config"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/secrets"
+)
+
+// Executor represents the function interface of the secret subcommand.
+type Executor func(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error
+
+// allow overriding the base URL, with --endpoint or for testing
+var secretsBaseURL string
+
+func makeSecrets(credentials *tcclient.Credentials) *secrets.Secrets {
+	return clientfactory.New(credentials).WithBaseURL("secrets", secretsBaseURL).Secrets()
+}
+
+func executeHelperE(f Executor) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		var creds *tcclient.Credentials
+		if config.Credentials != nil {
+			creds = config.Credentials.ToClientCredentials()
+		}
+
+		if endpoint := client.Endpoint(cmd.Flags(), "secrets"); endpoint != "" {
+			secretsBaseURL = endpoint
+		}
+		return f(creds, args, cmd.OutOrStdout(), cmd.Flags())
+	}
+}
@@ -12,9 +12,9 @@ import (
 
 	isatty "github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
 	"github.com/taskcluster/taskcluster-cli/cmds/root"
-	"github.com/taskcluster/taskcluster-cli/config"
-	tcclient "github.com/taskcluster/taskcluster-client-go"
 	"github.com/taskcluster/taskcluster-client-go/queue"
 	"github.com/taskcluster/taskcluster-worker/engines"
 	v2client "github.com/taskcluster/taskcluster-worker/plugins/interactive/shellclient"
@@ -42,12 +42,7 @@ func Execute(cmd *cobra.Command, args []string) error {
 
 	taskID := args[0]
 
-	var creds *tcclient.Credentials
-	if config.Credentials != nil {
-		creds = config.Credentials.ToClientCredentials()
-	}
-
-	q := queue.New(creds)
+	q := clientfactory.FromConfig().Queue()
 
 	err := checkTask(q, taskID)
 	if err != nil {
@@ -60,14 +55,15 @@ func Execute(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// client is an HTTP client that doesn't follow redirects.
-	client := &http.Client{
+	// noRedirect is an HTTP client that doesn't follow redirects.
+	noRedirect := &http.Client{
+		Transport: client.HTTPClient.Transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
-	resp, err := client.Get(sURL.String())
+	resp, err := noRedirect.Get(sURL.String())
 	if err != nil {
 		return err
 	}
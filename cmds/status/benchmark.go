@@ -0,0 +1,90 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shibukawa/configdir"
+	"github.com/spf13/cobra"
+)
+
+// Benchmark is the result of `status benchmark`, as output with --json.
+type Benchmark struct {
+	// Scrape is how long scraping the ping URLs with a cold cache took, in
+	// seconds
+	Scrape float64 `json:"scrape"`
+	// Ping is how long pinging all the services concurrently took, in seconds
+	Ping     float64         `json:"ping"`
+	Services []ServiceStatus `json:"services"`
+}
+
+// benchmarkCommand returns the hidden `status benchmark` command, which
+// measures how long a deployment takes to be checked from a cold cache.
+func benchmarkCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Times a cold cache scrape and a ping of all the services.",
+		Long: `Times scraping the ping URLs again, API references included, and pinging all
+the services concurrently, and reports both durations along with the latency
+of each service, slowest first. The cache of ping URLs is only replaced once
+the scrape succeeded.`,
+		Hidden: true,
+		RunE:   benchmark,
+	}
+	cmd.Flags().Bool("json", false, "Output the durations as JSON, in seconds.")
+	return cmd
+}
+
+func benchmark(cmd *cobra.Command, _ []string) error {
+	cache, err := Cache()
+	if err != nil {
+		return err
+	}
+	return benchmarkWithCache(cmd, cache)
+}
+
+// benchmarkWithCache is benchmark, caching the ping URLs in cache. The cached
+// ping URLs are never read, the scrape is always cold, and they are only
+// replaced by a successful scrape, as with --refresh.
+func benchmarkWithCache(cmd *cobra.Command, cache *configdir.Config) error {
+	var err error
+	// a cold scrape fetches all the API references too
+	refreshReferences = true
+	result := &Benchmark{}
+	start := time.Now()
+	if pingURLs, err = refreshCache(manifestURL, cache, pingURLsCachePath); err != nil {
+		return fmt.Errorf("could not refresh the cache: %v", err)
+	}
+	result.Scrape = time.Since(start).Seconds()
+
+	start = time.Now()
+	report := checkServices(context.Background(), pingURLs.Services())
+	result.Ping = time.Since(start).Seconds()
+	result.Services = report.Services
+	if err = sortServices(result.Services, "latency", true); err != nil {
+		return err
+	}
+
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return printJSON(cmd.OutOrStdout(), result)
+	}
+	printBenchmark(cmd.OutOrStdout(), result)
+	return nil
+}
+
+func printBenchmark(out io.Writer, result *Benchmark) {
+	fmt.Fprintf(out, "Scrape: %.3fs\n", result.Scrape)
+	fmt.Fprintf(out, "Ping:   %.3fs (%d services)\n", result.Ping, len(result.Services))
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, s := range result.Services {
+		fmt.Fprintf(w, "  %s\t%.3fs", s.Service, s.Latency)
+		if s.Error != "" {
+			fmt.Fprintf(w, "\terror: %s", s.Error)
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+}
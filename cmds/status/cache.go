@@ -0,0 +1,112 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// CacheInfo describes the cache of ping URLs, as output by `status cache info
+// --json`; durations are in seconds.
+type CacheInfo struct {
+	Path        string    `json:"path"`
+	ManifestURL string    `json:"manifestUrl"`
+	LastUpdated time.Time `json:"lastUpdated"`
+	Age         float64   `json:"age"`
+	TTL         float64   `json:"ttl"`
+	Expired     bool      `json:"expired"`
+	Partial     bool      `json:"partial"`
+	Services    int       `json:"services"`
+}
+
+// cacheCommand returns the `status cache` subtree, which manages the cache of
+// ping URLs.
+func cacheCommand() *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manages the cache of the ping URLs of the taskcluster services.",
+	}
+	cacheCmd.AddCommand(&cobra.Command{
+		Use:   "warm",
+		Short: "Scrapes the ping URLs of the taskcluster services and caches them.",
+		Long: `Scrapes the ping URLs of the taskcluster services and caches them, whether
+or not the cache has expired.
+
+This is meant to be run from shell initialization, so that completing service
+names never waits on a scrape.`,
+		RunE: warmCache,
+	})
+	infoCmd := &cobra.Command{
+		Use:   "info",
+		Short: "Describes the cache of ping URLs, e.g. when it was last updated.",
+		RunE:  cacheInfo,
+	}
+	infoCmd.Flags().Bool("json", false, "Output the description of the cache as JSON.")
+	cacheCmd.AddCommand(infoCmd)
+	return cacheCmd
+}
+
+func warmCache(cmd *cobra.Command, _ []string) error {
+	cache, err := Cache()
+	if err != nil {
+		return err
+	}
+	urls, err := RefreshPingURLs()
+	if err != nil {
+		return fmt.Errorf("could not refresh the cache: %v", err)
+	}
+	pingURLs = urls
+	fmt.Fprintf(cmd.OutOrStdout(), "Cached %d services in %s\n", len(urls), filepath.Join(cache.Path, pingURLsCachePath))
+	return nil
+}
+
+func cacheInfo(cmd *cobra.Command, _ []string) error {
+	cache, err := Cache()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(cache.Path, pingURLsCachePath)
+	if !cache.Exists(pingURLsCachePath) {
+		return fmt.Errorf("there is no cache at %s, run 'taskcluster status cache warm' to create it", path)
+	}
+	cachedURLs, err := ReadCachedURLsFile(cache, pingURLsCachePath)
+	if err != nil {
+		return fmt.Errorf("could not read the cache %s: %v", path, err)
+	}
+
+	info := &CacheInfo{
+		Path:        path,
+		ManifestURL: cachedURLs.Manifest(),
+		LastUpdated: cachedURLs.LastUpdated,
+		Age:         time.Since(cachedURLs.LastUpdated).Seconds(),
+		TTL:         cachedURLs.TTL().Seconds(),
+		Expired:     cachedURLs.Expired(cachedURLs.TTL()),
+		Partial:     cachedURLs.Partial,
+		Services:    len(cachedURLs.PingURLs),
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return printJSON(cmd.OutOrStdout(), info)
+	}
+	printCacheInfo(cmd.OutOrStdout(), info)
+	return nil
+}
+
+func printCacheInfo(out io.Writer, info *CacheInfo) {
+	expired := "no"
+	if info.Expired {
+		expired = "yes"
+	}
+	age := time.Duration(info.Age) * time.Second
+	fmt.Fprintf(out, "Path:         %s\n", info.Path)
+	fmt.Fprintf(out, "Manifest:     %s\n", info.ManifestURL)
+	fmt.Fprintf(out, "Last updated: %s (%s ago)\n", info.LastUpdated.UTC().Format(time.RFC3339), age)
+	fmt.Fprintf(out, "Expired:      %s (TTL %s)\n", expired, time.Duration(info.TTL)*time.Second)
+	if info.Partial {
+		fmt.Fprintf(out, "Services:     %d (partial, some API references could not be scraped)\n", info.Services)
+	} else {
+		fmt.Fprintf(out, "Services:     %d\n", info.Services)
+	}
+}
@@ -0,0 +1,61 @@
+package status
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/configdir"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestOpenCache(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	cache, err := openCache(&configdir.Config{Path: filepath.Join(dir, "cache"), Type: configdir.Cache})
+	assert.NoError(err)
+	assert.True(cache.Exists(""), "the cache folder should be created")
+}
+
+func TestOpenCacheUnwritable(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	// a file in place of a parent folder makes the cache folder impossible
+	// to create, even for root, which would ignore permissions
+	home := filepath.Join(dir, "home")
+	assert.NoError(ioutil.WriteFile(home, nil, 0444))
+
+	_, err = openCache(&configdir.Config{Path: filepath.Join(home, ".cache"), Type: configdir.Cache})
+	assert.Error(err)
+	assert.Contains(err.Error(), "could not create the cache folder")
+}
+
+func TestPrintCacheInfo(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	printCacheInfo(buf, &CacheInfo{
+		Path:        "/home/tester/.cache/taskcluster-cli/cmds/status/pingURLs.json",
+		ManifestURL: defaultManifestURL,
+		LastUpdated: time.Date(2017, 4, 10, 12, 0, 0, 0, time.UTC),
+		Age:         9000.5,
+		TTL:         cacheTTL.Seconds(),
+		Services:    12,
+	})
+	assert.Equal("Path:         /home/tester/.cache/taskcluster-cli/cmds/status/pingURLs.json\n"+
+		"Manifest:     https://references.taskcluster.net/manifest.json\n"+
+		"Last updated: 2017-04-10T12:00:00Z (2h30m0s ago)\n"+
+		"Expired:      no (TTL 24h0m0s)\n"+
+		"Services:     12\n", buf.String())
+}
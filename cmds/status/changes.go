@@ -0,0 +1,81 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/shibukawa/configdir"
+)
+
+// ReadCachedReport returns the report saved in the cache file at cachePath by
+// (*Report).Cache, or nil if there is none yet.
+func ReadCachedReport(cache *configdir.Config, cachePath string) (report *Report, err error) {
+	if !cache.Exists(cachePath) {
+		return nil, nil
+	}
+	var data []byte
+	data, err = cache.ReadFile(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the last status report: %v", err)
+	}
+	if err = json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("could not parse the last status report: %v", err)
+	}
+	return
+}
+
+// Cache writes the report to the cache file at cachePath, replacing the
+// previous one, so that the next run can show what changed with
+// --show-changes.
+func (report *Report) Cache(cache *configdir.Config, cachePath string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cache.WriteFile(cachePath, data)
+}
+
+// ChangesReport is the output of --json with --show-changes. Changes is
+// omitted on the first run, as there is nothing to compare with.
+type ChangesReport struct {
+	*Report
+	Since   *Report     `json:"-"`
+	Changes *Comparison `json:"changes,omitempty"`
+}
+
+// showChanges compares report to the one cached by the previous run, caches
+// report for the next one, and returns the result.
+func showChanges(report *Report) (*ChangesReport, error) {
+	cache, err := Cache()
+	if err != nil {
+		return nil, err
+	}
+	return showChangesWithCache(cache, report)
+}
+
+// showChangesWithCache is showChanges, with the previous report cached in
+// cache.
+func showChangesWithCache(cache *configdir.Config, report *Report) (*ChangesReport, error) {
+	previous, err := ReadCachedReport(cache, lastReportCachePath)
+	if err != nil {
+		return nil, err
+	}
+	if err = report.Cache(cache, lastReportCachePath); err != nil {
+		return nil, fmt.Errorf("could not save the status report: %v", err)
+	}
+
+	changes := &ChangesReport{Report: report, Since: previous}
+	if previous != nil {
+		changes.Changes = Compare(previous, report)
+	}
+	return changes, nil
+}
+
+func printChanges(out io.Writer, changes *ChangesReport) {
+	if changes.Since == nil {
+		fmt.Fprintln(out, "No previous run to compare with.")
+		return
+	}
+	printComparison(out, changes.Since.CheckedAt, changes.Changes)
+}
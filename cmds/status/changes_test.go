@@ -0,0 +1,67 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/configdir"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestShowChanges(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := &configdir.Config{Path: dir, Type: configdir.Cache}
+
+	first := &Report{
+		CheckedAt: time.Date(2017, 4, 11, 9, 0, 0, 0, time.UTC),
+		Services:  []ServiceStatus{{Service: "queue", Alive: true, Uptime: 100}},
+	}
+	changes, err := showChangesWithCache(cache, first)
+	assert.NoError(err)
+	assert.Nil(changes.Since, "there is nothing to compare the first run with")
+	assert.Nil(changes.Changes)
+	buf := &bytes.Buffer{}
+	printChanges(buf, changes)
+	assert.Equal("No previous run to compare with.\n", buf.String())
+	data, err := json.Marshal(changes)
+	assert.NoError(err)
+	assert.NotContains(string(data), "changes", "changes are omitted on the first run")
+
+	second := &Report{
+		CheckedAt: time.Date(2017, 4, 11, 10, 0, 0, 0, time.UTC),
+		Services:  []ServiceStatus{{Service: "queue", Error: "timeout"}},
+	}
+	changes, err = showChangesWithCache(cache, second)
+	assert.NoError(err)
+	assert.Equal(first.CheckedAt, changes.Since.CheckedAt, "the first report should have been cached")
+	assert.Equal([]string{"queue"}, changes.Changes.Down)
+	buf.Reset()
+	printChanges(buf, changes)
+	assert.Equal("Changes since 2017-04-11T09:00:00Z:\n  queue: went down\n", buf.String())
+
+	cached, err := ReadCachedReport(cache, lastReportCachePath)
+	assert.NoError(err)
+	assert.Equal(second.CheckedAt, cached.CheckedAt, "each run replaces the cached report")
+}
+
+func TestReadCachedReportInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := &configdir.Config{Path: dir, Type: configdir.Cache}
+
+	assert.NoError(cache.WriteFile(lastReportCachePath, []byte("not json")))
+	_, err = showChangesWithCache(cache, &Report{})
+	assert.Error(err)
+	assert.Contains(err.Error(), "could not parse the last status report")
+}
@@ -0,0 +1,101 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Comparison lists the services whose status changed between two reports.
+type Comparison struct {
+	Down      []string `json:"down"`
+	Recovered []string `json:"recovered"`
+	Restarted []string `json:"restarted"`
+}
+
+// Compare returns the services of current which went down, recovered, or
+// restarted since previous. A service is considered to have restarted if its
+// uptime is lower than it was in previous. Services absent from previous are
+// ignored.
+func Compare(previous, current *Report) *Comparison {
+	c := &Comparison{
+		Down:      []string{},
+		Recovered: []string{},
+		Restarted: []string{},
+	}
+	for _, cur := range current.Services {
+		prev, ok := previous.Lookup(cur.Service)
+		if !ok {
+			continue
+		}
+		switch {
+		case prev.Alive && !cur.Alive:
+			c.Down = append(c.Down, cur.Service)
+		case !prev.Alive && cur.Alive:
+			c.Recovered = append(c.Recovered, cur.Service)
+		case prev.Alive && cur.Alive && cur.Uptime < prev.Uptime:
+			c.Restarted = append(c.Restarted, cur.Service)
+		}
+	}
+	return c
+}
+
+// validateCompare rejects the flags which --compare can't honour: it outputs
+// no report, and renders the comparison either as text or as JSON.
+func validateCompare(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("compare") {
+		return nil
+	}
+	if cmd.Flags().Changed("output") {
+		return errors.New("--output can't be combined with --compare, which outputs no report")
+	}
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format != "text" && format != "json" {
+		return fmt.Errorf("--format %s can't be combined with --compare, which only renders text or JSON", format)
+	}
+	return nil
+}
+
+// compare prints the comparison between previous and current, and returns an
+// error if any service regressed.
+func compare(cmd *cobra.Command, previous, current *Report) error {
+	c := Compare(previous, current)
+
+	out := cmd.OutOrStdout()
+	if format, _ := outputFormat(cmd); format == "json" {
+		if err := printJSON(out, c); err != nil {
+			return err
+		}
+	} else {
+		printComparison(out, previous.CheckedAt, c)
+	}
+
+	if len(c.Down) > 0 {
+		return fmt.Errorf("%d service(s) went down since %s", len(c.Down), previous.CheckedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// printComparison writes the changes in c, which happened since the given
+// time, to out.
+func printComparison(out io.Writer, since time.Time, c *Comparison) {
+	fmt.Fprintf(out, "Changes since %s:\n", since.Format(time.RFC3339))
+	for _, service := range c.Down {
+		fmt.Fprintf(out, "  %s: went down\n", service)
+	}
+	for _, service := range c.Recovered {
+		fmt.Fprintf(out, "  %s: recovered\n", service)
+	}
+	for _, service := range c.Restarted {
+		fmt.Fprintf(out, "  %s: uptime reset, restarted since last check\n", service)
+	}
+	if len(c.Down)+len(c.Recovered)+len(c.Restarted) == 0 {
+		fmt.Fprintln(out, "  no changes")
+	}
+}
@@ -0,0 +1,114 @@
+package status
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := &Report{Services: []ServiceStatus{
+		{Service: "queue", Alive: true, Uptime: 100},
+		{Service: "auth", Alive: false},
+		{Service: "index", Alive: true, Uptime: 100},
+		{Service: "hooks", Alive: true, Uptime: 100},
+		{Service: "secrets", Alive: true, Uptime: 100},
+	}}
+	current := &Report{Services: []ServiceStatus{
+		{Service: "queue", Alive: false, Error: "timeout"},
+		{Service: "auth", Alive: true, Uptime: 5},
+		{Service: "index", Alive: true, Uptime: 5},
+		{Service: "hooks", Alive: true, Uptime: 200},
+		{Service: "purge-cache", Alive: false},
+	}}
+
+	assert.Equal(&Comparison{
+		Down:      []string{"queue"},
+		Recovered: []string{"auth"},
+		Restarted: []string{"index"},
+	}, Compare(previous, current), "services absent from the previous report are ignored")
+	assert.Equal(&Comparison{Down: []string{}, Recovered: []string{}, Restarted: []string{}}, Compare(current, current))
+
+	buf := &bytes.Buffer{}
+	since := time.Date(2017, 4, 11, 9, 0, 0, 0, time.UTC)
+	printComparison(buf, since, Compare(previous, current))
+	assert.Equal("Changes since 2017-04-11T09:00:00Z:\n"+
+		"  queue: went down\n"+
+		"  auth: recovered\n"+
+		"  index: uptime reset, restarted since last check\n", buf.String())
+}
+
+func compareCommand(flags ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().String("format", "text", "")
+	cmd.Flags().String("compare", "", "")
+	cmd.Flags().String("output", "", "")
+	cmd.Flags().Parse(flags)
+	return cmd
+}
+
+func TestCompareFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(validateCompare(compareCommand("--format", "html")), "without --compare")
+	assert.NoError(validateCompare(compareCommand("--compare", "old.json")))
+	assert.NoError(validateCompare(compareCommand("--compare", "old.json", "--format", "json")))
+	assert.NoError(validateCompare(compareCommand("--compare", "old.json", "--json", "--format", "html")), "--json overrides --format")
+	for _, format := range []string{"html", "junit", "template"} {
+		err := validateCompare(compareCommand("--compare", "old.json", "--format", format))
+		assert.Error(err, format)
+		assert.Contains(err.Error(), "--format "+format+" can't be combined with --compare")
+	}
+	assert.Error(validateCompare(compareCommand("--compare", "old.json", "--output", "new.json")))
+
+	previous := &Report{Services: []ServiceStatus{{Service: "queue", Alive: true}}}
+	current := &Report{Services: []ServiceStatus{{Service: "queue", Alive: true}}}
+	cmd := compareCommand("--compare", "old.json", "--format", "json")
+	buf := &bytes.Buffer{}
+	cmd.SetOutput(buf)
+	assert.NoError(compare(cmd, previous, current))
+	var c Comparison
+	assert.NoError(json.Unmarshal(buf.Bytes(), &c), "--format json renders the comparison as JSON")
+	assert.Equal(Comparison{Down: []string{}, Recovered: []string{}, Restarted: []string{}}, c)
+}
+
+func TestReadReportFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "report.json")
+	assert.NoError(ioutil.WriteFile(path, []byte(`{
+		"checkedAt": "2017-04-11T09:00:00Z",
+		"services": [{"service": "queue", "alive": true, "uptime": 42}]
+	}`), 0644))
+	report, err := ReadReportFile(path)
+	assert.NoError(err)
+	assert.Equal(time.Date(2017, 4, 11, 9, 0, 0, 0, time.UTC), report.CheckedAt)
+	s, ok := report.Lookup("queue")
+	assert.True(ok)
+	assert.Equal(42.0, s.Uptime)
+	_, ok = report.Lookup("auth")
+	assert.False(ok)
+
+	assert.NoError(ioutil.WriteFile(path, []byte("not json"), 0644))
+	_, err = ReadReportFile(path)
+	assert.Error(err)
+	assert.Contains(err.Error(), "could not parse status report")
+
+	_, err = ReadReportFile(filepath.Join(dir, "missing.json"))
+	assert.Error(err)
+	assert.Contains(err.Error(), "could not read status report")
+}
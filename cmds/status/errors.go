@@ -0,0 +1,96 @@
+package status
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+)
+
+const (
+	// errorTransient is the class of network errors, such as a DNS blip, a
+	// refused connection or a timeout, that may go away if retried.
+	errorTransient = "transient"
+	// errorPermanent is the class of network errors, such as an unknown host
+	// or an invalid TLS certificate, that retrying won't fix.
+	errorPermanent = "permanent"
+)
+
+// requestError is an error making a request, along with its class, which is
+// reported with the status of the service.
+type requestError struct {
+	Class string
+	Err   error
+}
+
+func (e *requestError) Error() string {
+	return fmt.Sprintf("%v (%s error)", e.Err, e.Class)
+}
+
+// ScrapeError lists the API references which could not be scraped for ping
+// URLs, while others could.
+type ScrapeError struct {
+	Failures []error
+}
+
+func (e *ScrapeError) Error() string {
+	failures := make([]string, 0, len(e.Failures))
+	for _, err := range e.Failures {
+		failures = append(failures, err.Error())
+	}
+	return fmt.Sprintf("could not scrape %d API references: %s", len(e.Failures), strings.Join(failures, "; "))
+}
+
+// classifyError tells whether err, returned by an HTTP client, is transient
+// or permanent. Errors which aren't recognized are deemed permanent, so that
+// they are not retried.
+func classifyError(err error) string {
+	for err != nil {
+		switch e := err.(type) {
+		case *net.DNSError:
+			// a name that doesn't exist is neither temporary nor a timeout
+			if e.Temporary() || e.Timeout() {
+				return errorTransient
+			}
+			return errorPermanent
+		case x509.UnknownAuthorityError, x509.CertificateInvalidError, x509.HostnameError,
+			*x509.UnknownAuthorityError, *x509.CertificateInvalidError, *x509.HostnameError:
+			return errorPermanent
+		case syscall.Errno:
+			switch e {
+			case syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ETIMEDOUT, syscall.EHOSTUNREACH, syscall.ENETUNREACH:
+				return errorTransient
+			}
+			return errorPermanent
+		}
+		if err == context.DeadlineExceeded {
+			return errorTransient
+		}
+		if e, ok := err.(net.Error); ok && e.Timeout() {
+			return errorTransient
+		}
+		err = unwrap(err)
+	}
+	return errorPermanent
+}
+
+// unwrap returns the error wrapped by err, or nil if there is none.
+func unwrap(err error) error {
+	switch e := err.(type) {
+	case *url.Error:
+		return e.Err
+	case *net.OpError:
+		return e.Err
+	case *os.SyscallError:
+		return e.Err
+	case interface {
+		Unwrap() error
+	}:
+		return e.Unwrap()
+	}
+	return nil
+}
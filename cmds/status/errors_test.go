@@ -0,0 +1,53 @@
+package status
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	assert := assert.New(t)
+
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://queue.taskcluster.net/v1/ping", Err: err}
+	}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	assert.Equal(errorTransient, classifyError(wrap(refused)))
+	assert.Equal(errorTransient, classifyError(wrap(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}})))
+	assert.Equal(errorTransient, classifyError(wrap(&net.DNSError{Err: "i/o timeout", IsTimeout: true})))
+	assert.Equal(errorTransient, classifyError(wrap(context.DeadlineExceeded)))
+	assert.Equal(errorPermanent, classifyError(wrap(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "nope.taskcluster.net"}})))
+	assert.Equal(errorPermanent, classifyError(wrap(x509.UnknownAuthorityError{})))
+	assert.Equal(errorPermanent, classifyError(wrap(x509.HostnameError{Host: "queue.taskcluster.net"})))
+	assert.Equal(errorPermanent, classifyError(errors.New("something else")))
+}
+
+func TestGetWithRetriesClassifiesErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	// a closed server refuses connections, which is transient, but the
+	// deadline doesn't leave time for a retry
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := getWithRetries(ctx, server.URL)
+	assert.Error(err)
+	e, ok := err.(*requestError)
+	assert.True(ok, "the error should tell its class")
+	assert.Equal(errorTransient, e.Class)
+	assert.Contains(e.Error(), "(transient error)")
+}
@@ -0,0 +1,119 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+)
+
+// Exit codes of the status command, before any remapping with --exit-code-map.
+const (
+	// ExitHealthy means every service checked is alive.
+	ExitHealthy = 0
+	// ExitUnhealthy means some services are down or could not be checked,
+	// or went down since the report given to --compare.
+	ExitUnhealthy = 1
+	// ExitUsage means the command was invoked incorrectly.
+	ExitUsage = 2
+	// ExitFailure means no service at all could be checked.
+	ExitFailure = 3
+)
+
+// exitCodeNames are the names of the exit codes accepted by --exit-code-map.
+var exitCodeNames = map[string]int{
+	"healthy":   ExitHealthy,
+	"unhealthy": ExitUnhealthy,
+	"usage":     ExitUsage,
+	"failure":   ExitFailure,
+}
+
+// exitCodeMap remaps the exit codes above, as given with --exit-code-map.
+var exitCodeMap = map[int]int{}
+
+// parseExitCodeMap parses entries of the form 'name=code', such as
+// 'unhealthy=7', where name is one of exitCodeNames.
+func parseExitCodeMap(values []string) (map[int]int, error) {
+	m := map[int]int{}
+	for _, v := range values {
+		p := strings.SplitN(v, "=", 2)
+		if len(p) != 2 {
+			return nil, fmt.Errorf("invalid exit code mapping '%s', mappings must be on the form 'name=code'", v)
+		}
+		from, ok := exitCodeNames[strings.TrimSpace(p[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown exit code name '%s', expected one of %s", p[0], strings.Join(exitCodeNameList(), ", "))
+		}
+		to, err := strconv.Atoi(strings.TrimSpace(p[1]))
+		if err != nil || to < 0 || to > 255 {
+			return nil, fmt.Errorf("invalid exit code '%s', exit codes must be between 0 and 255", p[1])
+		}
+		m[from] = to
+	}
+	return m, nil
+}
+
+func exitCodeNameList() []string {
+	names := make([]string, 0, len(exitCodeNames))
+	for name := range exitCodeNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// exit returns the error status should return to exit with code, remapped
+// according to exitCodeMap, where err describes why.
+func exit(cmd *cobra.Command, code int, err error) error {
+	name := exitCodeName(code)
+	if mapped, ok := exitCodeMap[code]; ok {
+		code = mapped
+	}
+	switch {
+	case code == 0:
+		return nil
+	case err == nil:
+		// healthy was remapped to a non-zero code; there is nothing to report
+		cmd.SilenceErrors = true
+		err = errors.New("all services are healthy")
+	}
+	cmd.SilenceUsage = name != "usage"
+	return &root.ExitError{Code: code, Err: root.WithCode(name, err)}
+}
+
+// exitCodeName returns the name of code in exitCodeNames, which is also the
+// code of the errors output with --format json.
+func exitCodeName(code int) string {
+	for name, c := range exitCodeNames {
+		if c == code {
+			return name
+		}
+	}
+	return root.ErrorCodeFailure
+}
+
+// reportExitCode returns the exit code matching the services of report. Only
+// the required services, if any, are deemed unhealthy when down.
+func reportExitCode(report *Report, required []string) (int, error) {
+	var unhealthy []string
+	failed := 0
+	for _, s := range report.Services {
+		if s.Error != "" {
+			failed++
+		}
+		if !s.Alive && isRequired(s.Service, required) {
+			unhealthy = append(unhealthy, s.Service)
+		}
+	}
+	switch {
+	case len(report.Services) > 0 && failed == len(report.Services):
+		return ExitFailure, errors.New("could not check the status of any service")
+	case len(unhealthy) > 0:
+		return ExitUnhealthy, fmt.Errorf("%d service(s) unhealthy: %s", len(unhealthy), strings.Join(unhealthy, ", "))
+	}
+	return ExitHealthy, nil
+}
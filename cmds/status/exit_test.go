@@ -0,0 +1,52 @@
+package status
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+)
+
+func TestParseExitCodeMap(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := parseExitCodeMap(nil)
+	assert.NoError(err)
+	assert.Empty(m)
+
+	m, err = parseExitCodeMap([]string{"unhealthy=7", " failure = 0 ", "healthy=255"})
+	assert.NoError(err)
+	assert.Equal(map[int]int{ExitUnhealthy: 7, ExitFailure: 0, ExitHealthy: 255}, m)
+
+	for _, v := range []string{"unhealthy", "sick=7", "unhealthy=seven", "unhealthy=-1", "unhealthy=256"} {
+		_, err = parseExitCodeMap([]string{v})
+		assert.Error(err, "'%s' should be rejected", v)
+	}
+}
+
+func TestExit(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(m map[int]int) { exitCodeMap = m }(exitCodeMap)
+	exitCodeMap = map[int]int{ExitUnhealthy: 7, ExitFailure: 0, ExitHealthy: 9}
+
+	cmd := &cobra.Command{}
+	err := exit(cmd, ExitUnhealthy, errors.New("queue is down"))
+	assert.Equal(7, root.ExitCode(err), "unhealthy is remapped")
+	assert.Equal("unhealthy", root.ErrorCode(err), "the error code keeps the name of the original exit code")
+	assert.True(cmd.SilenceUsage)
+
+	assert.NoError(exit(cmd, ExitFailure, errors.New("nothing could be checked")), "failure is remapped to success")
+
+	cmd = &cobra.Command{}
+	err = exit(cmd, ExitHealthy, nil)
+	assert.Equal(9, root.ExitCode(err), "healthy can be remapped to a failure")
+	assert.True(cmd.SilenceErrors, "there is no error to report when healthy")
+
+	cmd = &cobra.Command{}
+	err = exit(cmd, ExitUsage, errors.New("bad flag"))
+	assert.Equal(ExitUsage, root.ExitCode(err), "codes which aren't mapped are kept")
+	assert.False(cmd.SilenceUsage, "usage errors print the usage")
+}
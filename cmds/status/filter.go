@@ -0,0 +1,36 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// excludePattern is the regular expression given with --exclude, compiled by
+// preRun; services whose name it matches are not checked.
+var excludePattern *regexp.Regexp
+
+// parseExclude compiles the --exclude pattern, returning nil if there is none.
+func parseExclude(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --exclude pattern: %v", err)
+	}
+	return re, nil
+}
+
+// excludeServices returns the services whose name doesn't match re.
+func excludeServices(services []string, re *regexp.Regexp) []string {
+	if re == nil {
+		return services
+	}
+	result := make([]string, 0, len(services))
+	for _, service := range services {
+		if !re.MatchString(service) {
+			result = append(result, service)
+		}
+	}
+	return result
+}
@@ -0,0 +1,24 @@
+package status
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestExcludeServices(t *testing.T) {
+	assert := assert.New(t)
+
+	services := []string{"auth", "hooks", "queue", "secrets"}
+
+	re, err := parseExclude("")
+	assert.NoError(err)
+	assert.Equal(services, excludeServices(services, re))
+
+	re, err = parseExclude("secrets|^h")
+	assert.NoError(err)
+	assert.Equal([]string{"auth", "queue"}, excludeServices(services, re))
+
+	_, err = parseExclude("(")
+	assert.Error(err)
+}
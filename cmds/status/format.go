@@ -0,0 +1,128 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/client"
+)
+
+// outputTemplate is the template given with --template, parsed by preRun.
+var outputTemplate *template.Template
+
+// outputFormat returns the format selected with --format, which --json
+// overrides.
+func outputFormat(cmd *cobra.Command) (string, error) {
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return "json", nil
+	}
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "text", "json", "template", "html", "junit":
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported output format '%s', expected one of text, json, template, html, junit", format)
+}
+
+// parseTemplate parses the template given with --template, which is required
+// by --format template.
+func parseTemplate(cmd *cobra.Command) (*template.Template, error) {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return nil, err
+	}
+	text, _ := cmd.Flags().GetString("template")
+	if format != "template" {
+		if text != "" {
+			return nil, errors.New("--template requires --format template")
+		}
+		return nil, nil
+	}
+	if text == "" {
+		return nil, errors.New("--format template requires --template")
+	}
+	tmpl, err := template.New("status").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// validateFields checks the fields given with --fields, which only apply to
+// the JSON services of the report, or its summary with --summary-only.
+func validateFields(cmd *cobra.Command) error {
+	if len(client.Fields(cmd.Flags())) == 0 {
+		return nil
+	}
+	if format, _ := outputFormat(cmd); format != "json" {
+		return errors.New("--fields requires --format json")
+	}
+	for _, flag := range []string{"compare", "show-changes"} {
+		if cmd.Flags().Changed(flag) {
+			return fmt.Errorf("--fields can't be combined with --%s", flag)
+		}
+	}
+	if summaryOnly, _ := cmd.Flags().GetBool("summary-only"); summaryOnly {
+		return client.ValidateFields(cmd.Flags(), Summary{})
+	}
+	return client.ValidateFields(cmd.Flags(), ServiceStatus{})
+}
+
+// render writes report to out, in the format selected by the flags of cmd.
+func render(cmd *cobra.Command, out io.Writer, report *Report) error {
+	summaryOnly, _ := cmd.Flags().GetBool("summary-only")
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case format == "json" && summaryOnly:
+		summary, err := client.SelectFields(cmd.Flags(), report.Summary())
+		if err != nil {
+			return err
+		}
+		return printJSON(out, summary)
+	case format == "json":
+		services, err := client.SelectFields(cmd.Flags(), report.Services)
+		if err != nil {
+			return err
+		}
+		return printJSON(out, &struct {
+			CheckedAt time.Time   `json:"checkedAt"`
+			Services  interface{} `json:"services"`
+		}{report.CheckedAt, services})
+	case format == "template" && summaryOnly:
+		return executeTemplate(out, outputTemplate, report.Summary())
+	case format == "html":
+		return printHTML(out, report)
+	case format == "junit":
+		return printJUnit(out, report)
+	case format == "template":
+		for _, s := range report.Services {
+			if err := executeTemplate(out, outputTemplate, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case summaryOnly:
+		printSummary(out, report.Summary())
+	default:
+		printReport(out, report)
+	}
+	return nil
+}
+
+// executeTemplate renders data, a ServiceStatus or a Summary, through tmpl,
+// followed by a newline.
+func executeTemplate(out io.Writer, tmpl *template.Template, data interface{}) error {
+	if err := tmpl.Execute(out, data); err != nil {
+		return fmt.Errorf("could not render template: %v", err)
+	}
+	_, err := fmt.Fprintln(out)
+	return err
+}
@@ -0,0 +1,74 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+)
+
+func templateCommand(flags ...string) *cobra.Command {
+	cmd := fieldsCommand()
+	cmd.Flags().String("template", "", "")
+	cmd.Flags().Parse(flags)
+	return cmd
+}
+
+func TestParseTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	tmpl, err := parseTemplate(templateCommand())
+	assert.NoError(err)
+	assert.Nil(tmpl, "no template is needed outside of --format template")
+
+	tmpl, err = parseTemplate(templateCommand("--format", "template", "--template", "{{.Service}}"))
+	assert.NoError(err)
+	assert.NotNil(tmpl)
+
+	_, err = parseTemplate(templateCommand("--template", "{{.Service}}"))
+	assert.EqualError(err, "--template requires --format template")
+	_, err = parseTemplate(templateCommand("--json", "--template", "{{.Service}}"))
+	assert.Error(err, "--json overrides --format")
+	_, err = parseTemplate(templateCommand("--format", "template"))
+	assert.EqualError(err, "--format template requires --template")
+	_, err = parseTemplate(templateCommand("--format", "template", "--template", "{{.Service"))
+	assert.Error(err)
+	assert.Contains(err.Error(), "invalid template")
+	_, err = parseTemplate(templateCommand("--format", "yaml"))
+	assert.Error(err, "unknown formats are rejected")
+}
+
+func TestRenderTemplate(t *testing.T) {
+	assert := assert.New(t)
+
+	report := &Report{Services: []ServiceStatus{
+		{Service: "queue", Alive: true},
+		{Service: "index"},
+		{Service: "auth", Error: "timeout"},
+	}}
+	defer func(tmpl *template.Template) { outputTemplate = tmpl }(outputTemplate)
+	rendered := func(flags ...string) string {
+		cmd := templateCommand(flags...)
+		var err error
+		outputTemplate, err = parseTemplate(cmd)
+		assert.NoError(err)
+		buf := &bytes.Buffer{}
+		assert.NoError(render(cmd, buf, report))
+		return buf.String()
+	}
+
+	// the template is executed for each service
+	assert.Equal("queue true\nindex false\nauth false timeout\n",
+		rendered("--format", "template", "--template", "{{.Service}} {{.Alive}}{{with .Error}} {{.}}{{end}}"))
+	// or once for the summary
+	assert.Equal("1/3 alive, 1 down, 1 error(s)\n",
+		rendered("--format", "template", "--summary-only", "--template", "{{.Alive}}/{{.Total}} alive, {{.Down}} down, {{.Errors}} error(s)"))
+
+	cmd := templateCommand("--format", "template", "--template", "{{.Nope}}")
+	var err error
+	outputTemplate, err = parseTemplate(cmd)
+	assert.NoError(err)
+	assert.Error(render(cmd, &bytes.Buffer{}, report), "executing the template can fail")
+}
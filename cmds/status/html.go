@@ -0,0 +1,84 @@
+package status
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+)
+
+// htmlReport is the page rendered by --format html. It is self-contained, so
+// it can be sent by email or archived as is.
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"state":   htmlState,
+	"uptime":  htmlUptime,
+	"latency": htmlLatency,
+	"rfc3339": func(t time.Time) string { return t.UTC().Format(time.RFC3339) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Taskcluster status</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; }
+th, td { padding: 0.4em 1em; border-bottom: 1px solid #ddd; text-align: left; }
+.badge { display: inline-block; padding: 0.1em 0.6em; border-radius: 0.8em; color: #fff; font-size: 0.9em; }
+.alive { background: #2e7d32; }
+.down { background: #c62828; }
+.error { background: #ef6c00; }
+.details { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>Taskcluster status</h1>
+<p>{{with .Summary}}{{.Alive}}/{{.Total}} alive, {{.Down}} down, {{.Errors}} error(s){{end}}</p>
+<table>
+<tr><th>Service</th><th>Status</th><th>Uptime</th><th>Latency</th></tr>
+{{- range .Report.Services}}
+<tr>
+<td>{{.Service}}</td>
+<td>{{with state .}}<span class="badge {{.}}">{{.}}</span>{{end}}{{if .Error}} <span class="details">{{.Error}}</span>{{end}}</td>
+<td>{{uptime .}}</td>
+<td>{{latency .}}</td>
+</tr>
+{{- end}}
+</table>
+<p class="details">Generated at {{rfc3339 .Report.CheckedAt}}</p>
+</body>
+</html>
+`))
+
+func htmlState(s ServiceStatus) string {
+	switch {
+	case s.Error != "":
+		return "error"
+	case s.Alive:
+		return "alive"
+	default:
+		return "down"
+	}
+}
+
+func htmlUptime(s ServiceStatus) string {
+	if s.Error != "" || !s.Alive {
+		return "-"
+	}
+	return (time.Duration(s.Uptime) * time.Second).String()
+}
+
+func htmlLatency(s ServiceStatus) string {
+	return fmt.Sprintf("%.0fms", s.Latency*1000)
+}
+
+// printHTML writes report to out as an HTML page.
+func printHTML(out io.Writer, report *Report) error {
+	err := htmlReport.Execute(out, struct {
+		Report  *Report
+		Summary *Summary
+	}{report, report.Summary()})
+	if err != nil {
+		return fmt.Errorf("could not render the HTML report: %v", err)
+	}
+	return nil
+}
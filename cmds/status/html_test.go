@@ -0,0 +1,33 @@
+package status
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPrintHTML(t *testing.T) {
+	assert := assert.New(t)
+
+	report := &Report{
+		CheckedAt: time.Date(2017, 4, 11, 9, 0, 0, 0, time.UTC),
+		Services: []ServiceStatus{
+			{Service: "queue", Alive: true, Uptime: 60, Latency: 0.25},
+			{Service: "auth", Error: `<script>alert("pwned")</script>`},
+		},
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(printHTML(buf, report))
+
+	out := buf.String()
+	assert.Contains(out, "<p>1/2 alive, 0 down, 1 error(s)</p>")
+	assert.Contains(out, `<span class="badge alive">alive</span>`)
+	assert.Contains(out, "<td>1m0s</td>")
+	assert.Contains(out, "<td>250ms</td>")
+	assert.Contains(out, "Generated at 2017-04-11T09:00:00Z")
+	// errors are escaped
+	assert.NotContains(out, "<script>")
+	assert.Contains(out, "&lt;script&gt;alert(&#34;pwned&#34;)&lt;/script&gt;")
+}
@@ -0,0 +1,45 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	// preferHTTPS upgrades http ping URLs to https, see --prefer-https
+	preferHTTPS = true
+	// allowHTTP allows pinging over plain http, see --insecure-allow-http
+	allowHTTP = false
+	// warnings is where pinging over plain http is reported
+	warnings io.Writer = os.Stderr
+)
+
+// upgradeToHTTPS returns u with an https scheme if it is an http URL.
+func upgradeToHTTPS(u string) string {
+	if strings.HasPrefix(u, "http://") {
+		return "https://" + strings.TrimPrefix(u, "http://")
+	}
+	return u
+}
+
+// securePingURL returns the URL to ping a service at, given the ping URL it
+// was scraped with: an http URL is upgraded to https with --prefer-https, as
+// it may have been cached without it, and refused unless --insecure-allow-http
+// is given, in which case pinging it is warned about.
+func securePingURL(u string) (string, error) {
+	if preferHTTPS {
+		u = upgradeToHTTPS(u)
+	}
+	if !strings.HasPrefix(u, "http://") {
+		return u, nil
+	}
+	if !allowHTTP {
+		return "", fmt.Errorf("refusing to ping %v over plain http, see --insecure-allow-http", u)
+	}
+	color.New(color.FgRed, color.Bold).Fprintf(warnings, "WARNING: pinging %v over plain http\n", u)
+	return u, nil
+}
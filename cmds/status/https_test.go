@@ -0,0 +1,38 @@
+package status
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSecurePingURL(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(prefer, allow bool, w io.Writer) {
+		preferHTTPS, allowHTTP, warnings = prefer, allow, w
+	}(preferHTTPS, allowHTTP, warnings)
+	buf := &bytes.Buffer{}
+	warnings = buf
+
+	preferHTTPS, allowHTTP = true, false
+	u, err := securePingURL("http://queue.taskcluster.net/v1/ping")
+	assert.NoError(err)
+	assert.Equal("https://queue.taskcluster.net/v1/ping", u)
+
+	preferHTTPS = false
+	_, err = securePingURL("http://queue.taskcluster.net/v1/ping")
+	assert.Error(err, "plain http should be refused")
+	u, err = securePingURL("https://queue.taskcluster.net/v1/ping")
+	assert.NoError(err)
+	assert.Equal("https://queue.taskcluster.net/v1/ping", u)
+	assert.Equal("", buf.String())
+
+	allowHTTP = true
+	u, err = securePingURL("http://queue.taskcluster.net/v1/ping")
+	assert.NoError(err)
+	assert.Equal("http://queue.taskcluster.net/v1/ping", u)
+	assert.Contains(buf.String(), "WARNING: pinging http://queue.taskcluster.net/v1/ping over plain http")
+}
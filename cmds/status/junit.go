@@ -0,0 +1,77 @@
+package status
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type (
+	// junitTestSuite is the report rendered by --format junit, for CI systems
+	// which display JUnit XML test results.
+	junitTestSuite struct {
+		XMLName   xml.Name        `xml:"testsuite"`
+		Name      string          `xml:"name,attr"`
+		Tests     int             `xml:"tests,attr"`
+		Failures  int             `xml:"failures,attr"`
+		Time      string          `xml:"time,attr"`
+		Timestamp string          `xml:"timestamp,attr"`
+		TestCases []junitTestCase `xml:"testcase"`
+	}
+
+	// junitTestCase is the result of checking a single service.
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Time      string        `xml:"time,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+	}
+)
+
+// junitSeconds formats seconds the way JUnit reports expect them.
+func junitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// printJUnit writes report to out as a JUnit XML test suite, with a test case
+// per service. Services which are down or could not be checked fail.
+func printJUnit(out io.Writer, report *Report) error {
+	suite := junitTestSuite{
+		Name:      "taskcluster-status",
+		Tests:     len(report.Services),
+		Timestamp: report.CheckedAt.UTC().Format(time.RFC3339),
+	}
+	var total float64
+	for _, s := range report.Services {
+		testCase := junitTestCase{
+			Name:      s.Service,
+			ClassName: "taskcluster-status." + s.Service,
+			Time:      junitSeconds(s.Latency),
+		}
+		switch {
+		case s.Error != "":
+			testCase.Failure = &junitFailure{Message: s.Error, Type: "error"}
+		case !s.Alive:
+			testCase.Failure = &junitFailure{Message: s.Service + " is down", Type: "down"}
+		}
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		total += s.Latency
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+	suite.Time = junitSeconds(total)
+
+	data, err := xml.MarshalIndent(&suite, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not render the JUnit report: %v", err)
+	}
+	_, err = fmt.Fprintf(out, "%s%s\n", xml.Header, data)
+	return err
+}
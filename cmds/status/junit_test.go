@@ -0,0 +1,41 @@
+package status
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPrintJUnit(t *testing.T) {
+	assert := assert.New(t)
+
+	report := &Report{
+		CheckedAt: time.Date(2017, 4, 11, 9, 0, 0, 0, time.UTC),
+		Services: []ServiceStatus{
+			{Service: "queue", Alive: true, Latency: 0.25},
+			{Service: "index", Latency: 0.5},
+			{Service: "auth", Error: "connection refused & more"},
+		},
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(printJUnit(buf, report))
+	assert.Contains(buf.String(), xml.Header)
+
+	var suite junitTestSuite
+	assert.NoError(xml.Unmarshal(buf.Bytes(), &suite))
+	assert.Equal("taskcluster-status", suite.Name)
+	assert.Equal(3, suite.Tests)
+	assert.Equal(2, suite.Failures)
+	assert.Equal("0.750", suite.Time)
+	assert.Equal("2017-04-11T09:00:00Z", suite.Timestamp)
+
+	assert.Len(suite.TestCases, 3)
+	assert.Equal("queue", suite.TestCases[0].Name)
+	assert.Equal("0.250", suite.TestCases[0].Time)
+	assert.Nil(suite.TestCases[0].Failure)
+	assert.Equal(&junitFailure{Message: "index is down", Type: "down"}, suite.TestCases[1].Failure)
+	assert.Equal(&junitFailure{Message: "connection refused & more", Type: "error"}, suite.TestCases[2].Failure)
+}
@@ -0,0 +1,40 @@
+package status
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// dumpManifestCommand returns the `status dump-manifest` command, which
+// prints the manifest the ping URLs are scraped from.
+func dumpManifestCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "dump-manifest",
+		Short: "Prints the API references listed by the manifest, as JSON.",
+		Long: `Fetches the manifest given with --manifest-url and prints the URL of the API
+reference of each service as JSON, without fetching the references, to tell
+problems with the manifest from problems with the references when scraping
+the ping URLs fails.`,
+		RunE: dumpManifest,
+	}
+}
+
+func dumpManifest(cmd *cobra.Command, _ []string) error {
+	apis, err := fetchManifest(manifestURL)
+	if err != nil {
+		return fmt.Errorf("could not fetch the manifest %v: %v", manifestURL, err)
+	}
+	return printJSON(cmd.OutOrStdout(), apis)
+}
+
+// fetchManifest returns the URL of the API reference of each service listed
+// by the manifest at manifestURL.
+func fetchManifest(manifestURL string) (map[string]string, error) {
+	var apis map[string]string
+	if err := objectFromJSONURL(context.Background(), manifestURL, &apis); err != nil {
+		return nil, err
+	}
+	return apis, nil
+}
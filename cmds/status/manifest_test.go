@@ -0,0 +1,40 @@
+package status
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestDumpManifest(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	manifest := filepath.Join(dir, "manifest.json")
+	assert.NoError(ioutil.WriteFile(manifest, []byte(`{
+		"Queue": "https://references.taskcluster.net/queue/v1/api.json",
+		"Auth": "https://references.taskcluster.net/auth/v1/api.json"
+	}`), 0644))
+	defer func(u string) { manifestURL = u }(manifestURL)
+	manifestURL = "file://" + filepath.ToSlash(manifest)
+
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	assert.NoError(dumpManifest(cmd, nil))
+	assert.JSONEq(`{
+		"Auth": "https://references.taskcluster.net/auth/v1/api.json",
+		"Queue": "https://references.taskcluster.net/queue/v1/api.json"
+	}`, buf.String())
+
+	manifestURL = "file://" + filepath.ToSlash(filepath.Join(dir, "missing.json"))
+	assert.Error(dumpManifest(cmd, nil))
+}
@@ -0,0 +1,52 @@
+package status
+
+import "sync"
+
+// memo holds the ping URLs loaded by this process, so that a --watch loop or
+// several commands needing them share a single, consistent, load.
+var memo = &memoizedPingURLs{}
+
+// memoizedPingURLs are the ping URLs loaded from a manifest URL, safe for
+// concurrent use.
+type memoizedPingURLs struct {
+	mu          sync.Mutex
+	manifestURL string
+	pingURLs    PingURLs
+}
+
+// get returns the ping URLs of manifestURL, calling load only if they were
+// not loaded yet, or were loaded for another manifest URL. Failed loads are
+// not memoized.
+func (m *memoizedPingURLs) get(manifestURL string, load func() (PingURLs, error)) (PingURLs, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.pingURLs != nil && m.manifestURL == manifestURL {
+		return m.pingURLs, nil
+	}
+	p, err := load()
+	if err != nil {
+		return nil, err
+	}
+	m.manifestURL, m.pingURLs = manifestURL, p
+	return p, nil
+}
+
+// forget drops the memoized ping URLs, so that the next get loads them again.
+func (m *memoizedPingURLs) forget() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.manifestURL, m.pingURLs = "", nil
+}
+
+// RefreshPingURLs scrapes the ping URLs again, caching them, as if the cache
+// had expired, and returns them; later calls to NewPingURLs return them too.
+func RefreshPingURLs() (PingURLs, error) {
+	memo.forget()
+	return memo.get(manifestURL, func() (PingURLs, error) {
+		cache, err := Cache()
+		if err != nil {
+			return nil, err
+		}
+		return refreshCache(manifestURL, cache, pingURLsCachePath)
+	})
+}
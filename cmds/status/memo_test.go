@@ -0,0 +1,53 @@
+package status
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestMemoizedPingURLs(t *testing.T) {
+	assert := assert.New(t)
+
+	m := &memoizedPingURLs{}
+	loads := 0
+	load := func() (PingURLs, error) {
+		loads++
+		return PingURLs{"queue": "https://queue.taskcluster.net/v1/ping"}, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]PingURLs, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = m.get("https://references.taskcluster.net/manifest.json", load)
+		}(i)
+	}
+	wg.Wait()
+	for _, p := range results {
+		assert.Len(p, 1)
+	}
+	assert.Equal(1, loads, "the ping URLs should be loaded once")
+
+	_, err := m.get("file:///tmp/manifest.json", load)
+	assert.NoError(err)
+	assert.Equal(2, loads, "another manifest has other ping URLs")
+
+	m.forget()
+	_, err = m.get("file:///tmp/manifest.json", load)
+	assert.NoError(err)
+	assert.Equal(3, loads, "forgotten ping URLs should be loaded again")
+
+	m.forget()
+	_, err = m.get("file:///tmp/manifest.json", func() (PingURLs, error) {
+		return nil, errors.New("boom")
+	})
+	assert.Error(err)
+	_, err = m.get("file:///tmp/manifest.json", load)
+	assert.NoError(err)
+	assert.Equal(4, loads, "failures shouldn't be memoized")
+}
@@ -0,0 +1,44 @@
+package status
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// parseBaseURLOverrides parses entries of the form 'service=url', as given
+// with --baseurl-override, into the ping URLs replacing those of p for these
+// services, e.g. 'queue=https://queue.staging.example.com/v1' pings
+// https://queue.staging.example.com/v1/ping.
+func parseBaseURLOverrides(values []string, p PingURLs) (map[string]string, error) {
+	overrides := make(map[string]string, len(values))
+	for _, v := range values {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid base URL override '%s', overrides must be on the form 'service=url'", v)
+		}
+		service := strings.TrimSpace(parts[0])
+		if _, ok := p[service]; !ok {
+			return nil, fmt.Errorf("unknown service '%s' given to --baseurl-override", service)
+		}
+		baseURL := strings.TrimRight(strings.TrimSpace(parts[1]), "/")
+		u, err := url.Parse(baseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid base URL '%s' for %s, expected an http or https URL", parts[1], service)
+		}
+		overrides[service] = baseURL + "/ping"
+	}
+	return overrides, nil
+}
+
+// override returns a copy of p with the ping URLs of overrides.
+func (p PingURLs) override(overrides map[string]string) PingURLs {
+	result := make(PingURLs, len(p))
+	for service, u := range p {
+		result[service] = u
+	}
+	for service, u := range overrides {
+		result[service] = u
+	}
+	return result
+}
@@ -0,0 +1,29 @@
+package status
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestParseBaseURLOverrides(t *testing.T) {
+	assert := assert.New(t)
+
+	p := PingURLs{
+		"queue": "https://queue.taskcluster.net/v1/ping",
+		"auth":  "https://auth.taskcluster.net/v1/ping",
+	}
+	overrides, err := parseBaseURLOverrides([]string{"queue = https://queue.staging.example.com/v1/"}, p)
+	assert.NoError(err)
+	assert.Equal(map[string]string{"queue": "https://queue.staging.example.com/v1/ping"}, overrides)
+	assert.Equal(PingURLs{
+		"queue": "https://queue.staging.example.com/v1/ping",
+		"auth":  "https://auth.taskcluster.net/v1/ping",
+	}, p.override(overrides))
+	assert.Equal("https://queue.taskcluster.net/v1/ping", p["queue"], "the scraped ping URLs are left alone")
+
+	for _, v := range []string{"queue", "nope=https://example.com", "queue=ftp://example.com", "queue=not a url"} {
+		_, err = parseBaseURLOverrides([]string{v}, p)
+		assert.Error(err, v)
+	}
+}
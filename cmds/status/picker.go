@@ -0,0 +1,80 @@
+package status
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+var (
+	// stdin is where --interactive reads the choice of services from; it is
+	// a variable so tests can replace it
+	stdin io.Reader = os.Stdin
+	// isInteractive tells whether the user can be prompted, which requires
+	// both stdin and stderr, where the prompt goes, to be terminals
+	isInteractive = func() bool {
+		return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stderr.Fd())
+	}
+)
+
+// pickServices lists services on out, and prompts for which of them to check
+// until it reads a valid choice from in. The choice is a comma separated list
+// of numbers and ranges, e.g. 1,3-5; an empty choice picks all the services.
+func pickServices(in io.Reader, out io.Writer, services []string) ([]string, error) {
+	for i, service := range services {
+		fmt.Fprintf(out, "%3d) %s\n", i+1, service)
+	}
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(out, "Services to check (e.g. 1,3-5; empty for all): ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			if err := scanner.Err(); err != nil {
+				return nil, fmt.Errorf("could not read the choice of services: %v", err)
+			}
+			return services, nil
+		}
+		picked, err := parseChoice(scanner.Text(), services)
+		if err == nil {
+			return picked, nil
+		}
+		fmt.Fprintln(out, err)
+	}
+}
+
+// parseChoice returns the services picked by choice, in the order of
+// services, see pickServices.
+func parseChoice(choice string, services []string) ([]string, error) {
+	choice = strings.TrimSpace(choice)
+	if choice == "" {
+		return services, nil
+	}
+	picked := make([]bool, len(services))
+	for _, part := range strings.Split(choice, ",") {
+		part = strings.TrimSpace(part)
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		last := first
+		if err == nil && len(bounds) == 2 {
+			last, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+		}
+		if err != nil || first < 1 || last > len(services) || first > last {
+			return nil, fmt.Errorf("invalid choice '%s', expected numbers between 1 and %d", part, len(services))
+		}
+		for i := first; i <= last; i++ {
+			picked[i-1] = true
+		}
+	}
+	result := []string{}
+	for i, service := range services {
+		if picked[i] {
+			result = append(result, service)
+		}
+	}
+	return result, nil
+}
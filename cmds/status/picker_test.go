@@ -0,0 +1,46 @@
+package status
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestParseChoice(t *testing.T) {
+	assert := assert.New(t)
+
+	services := []string{"auth", "hooks", "index", "queue", "secrets"}
+
+	picked, err := parseChoice("", services)
+	assert.NoError(err)
+	assert.Equal(services, picked)
+
+	picked, err = parseChoice(" 4, 1-2 ,2", services)
+	assert.NoError(err)
+	assert.Equal([]string{"auth", "hooks", "queue"}, picked)
+
+	for _, choice := range []string{"0", "6", "3-1", "a", "1-", "1,,2"} {
+		_, err = parseChoice(choice, services)
+		assert.Error(err, "%q should be rejected", choice)
+	}
+}
+
+func TestPickServices(t *testing.T) {
+	assert := assert.New(t)
+
+	// an invalid choice prompts again
+	buf := &bytes.Buffer{}
+	picked, err := pickServices(strings.NewReader("9\n2\n"), buf, []string{"auth", "queue"})
+	assert.NoError(err)
+	assert.Equal([]string{"queue"}, picked)
+	assert.Equal("  1) auth\n  2) queue\n"+
+		"Services to check (e.g. 1,3-5; empty for all): invalid choice '9', expected numbers between 1 and 2\n"+
+		"Services to check (e.g. 1,3-5; empty for all): ", buf.String())
+
+	// the end of the input picks all the services
+	picked, err = pickServices(strings.NewReader(""), &bytes.Buffer{}, []string{"auth", "queue"})
+	assert.NoError(err)
+	assert.Equal([]string{"auth", "queue"}, picked)
+}
@@ -0,0 +1,25 @@
+package status
+
+import (
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+)
+
+var (
+	// progress is where scraping and caching are reported, apart from the
+	// report itself so that it stays clean on stdout
+	progress io.Writer = os.Stderr
+	// quietProgress silences the progress messages, see --quiet-progress
+	quietProgress = false
+)
+
+// reportProgress writes a progress message, in color c, unless progress
+// messages are silenced.
+func reportProgress(c color.Attribute, format string, a ...interface{}) {
+	if quietProgress {
+		return
+	}
+	color.New(c).Fprintf(progress, format+"\n", a...)
+}
@@ -0,0 +1,26 @@
+package status
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/fatih/color"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestReportProgress(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(w io.Writer, quiet bool) { progress, quietProgress = w, quiet }(progress, quietProgress)
+	buf := &bytes.Buffer{}
+	progress = buf
+
+	reportProgress(color.FgYellow, "Scraping ping URLs from %v", "https://example.com")
+	assert.Contains(buf.String(), "Scraping ping URLs from https://example.com\n")
+
+	buf.Reset()
+	quietProgress = true
+	reportProgress(color.FgYellow, "Scraping ping URLs from %v", "https://example.com")
+	assert.Equal("", buf.String(), "--quiet-progress should silence progress messages")
+}
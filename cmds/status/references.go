@@ -0,0 +1,89 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/configdir"
+)
+
+var (
+	// referencesCachePath is where the API references are cached, by URL, in
+	// the cache folder
+	referencesCachePath = filepath.Join("cmds", "status", "references.json")
+	// referenceTTL is how long a cached API reference is used before it is
+	// fetched again; references change much less often than deployments
+	referenceTTL = 7 * 24 * time.Hour
+	// refreshReferences bypasses the cache of API references, see
+	// --refresh-references
+	refreshReferences = false
+)
+
+// cachedReference is the part of an API reference needed to find its ping
+// URL, along with when it was fetched.
+type cachedReference struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+	Reference *API      `json:"reference"`
+}
+
+// referenceCache holds the API references fetched recently, keyed by their
+// URL, so that refreshing the ping URLs only needs to fetch the manifest. It
+// is best effort: a cache which can't be read or written is done without.
+type referenceCache struct {
+	cache      *configdir.Config
+	references map[string]cachedReference
+	changed    bool
+}
+
+// openReferenceCache reads the cached API references from cache, which may be
+// nil when there is no cache folder.
+func openReferenceCache(cache *configdir.Config) *referenceCache {
+	c := &referenceCache{cache: cache, references: map[string]cachedReference{}}
+	if cache == nil || refreshReferences {
+		return c
+	}
+	if data, err := cache.ReadFile(referencesCachePath); err == nil {
+		if err = json.Unmarshal(data, &c.references); err != nil {
+			// start over rather than fail on a corrupt cache
+			c.references = map[string]cachedReference{}
+		}
+	}
+	return c
+}
+
+// reference returns the API reference at apiURL, from the cache if it was
+// fetched less than referenceTTL ago. References read from files are never
+// cached, they are cheap to read again.
+func (c *referenceCache) reference(apiURL string) (*API, error) {
+	if _, ok := filePath(apiURL); ok {
+		reference := new(API)
+		return reference, objectFromJSONURL(context.Background(), apiURL, reference)
+	}
+	if cached, ok := c.references[apiURL]; ok && cached.Reference != nil && time.Since(cached.FetchedAt) < referenceTTL {
+		return cached.Reference, nil
+	}
+	reference := new(API)
+	if err := objectFromJSONURL(context.Background(), apiURL, reference); err != nil {
+		return nil, err
+	}
+	c.references[apiURL] = cachedReference{FetchedAt: time.Now(), Reference: reference}
+	c.changed = true
+	return reference, nil
+}
+
+// save writes the cached API references back, if some were fetched.
+func (c *referenceCache) save() {
+	if c.cache == nil || !c.changed {
+		return
+	}
+	data, err := json.MarshalIndent(c.references, "", "  ")
+	if err == nil {
+		err = c.cache.WriteFile(referencesCachePath, data)
+	}
+	if err != nil {
+		color.New(color.FgYellow).Fprintf(warnings, "WARNING: could not cache the API references: %v\n", err)
+	}
+}
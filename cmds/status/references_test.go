@@ -0,0 +1,66 @@
+package status
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/configdir"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestReferenceCache(t *testing.T) {
+	assert := assert.New(t)
+
+	fetches := 0
+	handler := http.NewServeMux()
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	handler.HandleFunc("/manifest.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"Queue": "%s/queue.json"}`, server.URL)
+	})
+	handler.HandleFunc("/queue.json", func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"baseUrl": "https://queue.taskcluster.net/v1", "entries": [{"name": "ping", "route": "/ping"}]}`)
+	})
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := &configdir.Config{Path: filepath.Join(dir, "cache"), Type: configdir.Cache}
+	defer func(w io.Writer) { progress = w }(progress)
+	progress = ioutil.Discard
+
+	expected := PingURLs{"queue": "https://queue.taskcluster.net/v1/ping"}
+	p, err := RefreshCache(server.URL+"/manifest.json", cache, pingURLsCachePath)
+	assert.NoError(err)
+	assert.Equal(expected, p)
+	assert.Equal(1, fetches)
+	assert.True(cache.Exists(referencesCachePath))
+
+	p, err = RefreshCache(server.URL+"/manifest.json", cache, pingURLsCachePath)
+	assert.NoError(err)
+	assert.Equal(expected, p)
+	assert.Equal(1, fetches, "the cached reference should be reused")
+
+	defer func(d time.Duration) { referenceTTL = d }(referenceTTL)
+	referenceTTL = 0
+	_, err = RefreshCache(server.URL+"/manifest.json", cache, pingURLsCachePath)
+	assert.NoError(err)
+	assert.Equal(2, fetches, "expired references should be fetched again")
+	referenceTTL = time.Hour
+
+	defer func(refresh bool) { refreshReferences = refresh }(refreshReferences)
+	refreshReferences = true
+	_, err = RefreshCache(server.URL+"/manifest.json", cache, pingURLsCachePath)
+	assert.NoError(err)
+	assert.Equal(3, fetches, "--refresh-references should bypass the cache")
+}
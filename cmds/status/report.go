@@ -0,0 +1,159 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/taskcluster/taskcluster-cli/client"
+)
+
+type (
+	// ServiceStatus is the result of checking the status of a single service.
+	ServiceStatus struct {
+		Service string  `json:"service"`
+		Alive   bool    `json:"alive"`
+		Uptime  float64 `json:"uptime"`
+		// Latency is how long the ping took, in seconds
+		Latency float64 `json:"latency"`
+		Error   string  `json:"error,omitempty"`
+		// ErrorClass is "transient" or "permanent" for network errors
+		ErrorClass string `json:"errorClass,omitempty"`
+	}
+
+	// Report is the result of checking the status of a set of services, as
+	// output with --json.
+	Report struct {
+		CheckedAt time.Time       `json:"checkedAt"`
+		Services  []ServiceStatus `json:"services"`
+	}
+
+	// Summary holds the aggregate numbers of a Report, as output with
+	// --summary-only. Services which could not be checked are counted in
+	// Errors rather than in Down.
+	Summary struct {
+		Total     int       `json:"total"`
+		Alive     int       `json:"alive"`
+		Down      int       `json:"down"`
+		Errors    int       `json:"errors"`
+		CheckedAt time.Time `json:"checkedAt"`
+	}
+)
+
+// checkServices pings each of the given services concurrently and collects
+// the results, in the order of services. The pings are given up once ctx is
+// done.
+func checkServices(ctx context.Context, services []string) *Report {
+	report := &Report{
+		CheckedAt: time.Now().UTC(),
+		Services:  make([]ServiceStatus, len(services)),
+	}
+	var wg sync.WaitGroup
+	for i, service := range services {
+		wg.Add(1)
+		go func(i int, service string) {
+			defer wg.Done()
+			report.Services[i] = respbody(ctx, service)
+		}(i, service)
+	}
+	wg.Wait()
+	return report
+}
+
+// respbody queries the ping endpoint of service and returns its status.
+func respbody(ctx context.Context, service string) ServiceStatus {
+	result := ServiceStatus{Service: service}
+	var servstat PingResponse
+	u, err := securePingURL(pingURLs[service])
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	start := time.Now()
+	err = objectFromJSONURL(ctx, u, &servstat)
+	result.Latency = time.Since(start).Seconds()
+	if err != nil {
+		result.Error = err.Error()
+		if e, ok := err.(*requestError); ok {
+			result.ErrorClass = e.Class
+		}
+		return result
+	}
+	result.Alive = servstat.Alive
+	result.Uptime = servstat.Uptime
+	return result
+}
+
+// ReadReportFile returns the *Report saved with --json in the file at path.
+func ReadReportFile(path string) (report *Report, err error) {
+	var data []byte
+	data, err = ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read status report %s: %v", path, err)
+	}
+	if err = json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("could not parse status report %s: %v", path, err)
+	}
+	return
+}
+
+// Lookup returns the status of service in the report, if present.
+func (report *Report) Lookup(service string) (ServiceStatus, bool) {
+	for _, s := range report.Services {
+		if s.Service == service {
+			return s, true
+		}
+	}
+	return ServiceStatus{}, false
+}
+
+// Summary returns the aggregate numbers of the report.
+func (report *Report) Summary() *Summary {
+	summary := &Summary{
+		Total:     len(report.Services),
+		CheckedAt: report.CheckedAt,
+	}
+	for _, s := range report.Services {
+		switch {
+		case s.Error != "":
+			summary.Errors++
+		case s.Alive:
+			summary.Alive++
+		default:
+			summary.Down++
+		}
+	}
+	return summary
+}
+
+func printJSON(out io.Writer, v interface{}) error {
+	data, err := client.MarshalJSON(v)
+	if err != nil {
+		return fmt.Errorf("could not marshal status report: %v", err)
+	}
+	fmt.Fprintln(out, string(data))
+	return nil
+}
+
+func printReport(out io.Writer, report *Report) {
+	for _, s := range report.Services {
+		fmt.Fprintf(out, "      %v\n", s.Service)
+		switch {
+		case s.Error != "":
+			color.New(color.FgRed).Fprintf(out, "      Error: %v\n", s.Error)
+		case s.Alive:
+			color.New(color.FgGreen).Fprintf(out, "      %v\n", "Alive")
+		default:
+			color.New(color.FgRed).Fprintf(out, "      %v\n", "Down")
+		}
+	}
+}
+
+func printSummary(out io.Writer, summary *Summary) {
+	fmt.Fprintf(out, "%d/%d alive, %d down, %d error(s)\n", summary.Alive, summary.Total, summary.Down, summary.Errors)
+}
@@ -0,0 +1,232 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/configdir"
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+)
+
+func TestCheckServices(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow/ping" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"alive": true, "uptime": 42}`)
+	}))
+	defer server.Close()
+
+	// the fake server only speaks plain http
+	defer func(prefer, allow bool, w io.Writer) {
+		preferHTTPS, allowHTTP, warnings = prefer, allow, w
+	}(preferHTTPS, allowHTTP, warnings)
+	preferHTTPS, allowHTTP, warnings = false, true, ioutil.Discard
+
+	defer func(p PingURLs) { pingURLs = p }(pingURLs)
+	pingURLs = PingURLs{
+		"slow": server.URL + "/slow/ping",
+		"fast": server.URL + "/fast/ping",
+	}
+	// services are checked concurrently, but reported in the order given
+	report := checkServices(context.Background(), []string{"slow", "fast"})
+	assert.Len(report.Services, 2)
+	assert.Equal("slow", report.Services[0].Service)
+	assert.Equal("fast", report.Services[1].Service)
+	for _, s := range report.Services {
+		assert.True(s.Alive)
+		assert.Equal(42.0, s.Uptime)
+	}
+	assert.True(report.Services[0].Latency > report.Services[1].Latency)
+}
+
+func TestReportSummary(t *testing.T) {
+	assert := assert.New(t)
+
+	checkedAt := time.Date(2017, 4, 11, 9, 0, 0, 0, time.UTC)
+	report := &Report{CheckedAt: checkedAt, Services: []ServiceStatus{
+		{Service: "queue", Alive: true},
+		{Service: "auth", Alive: true},
+		{Service: "index"},
+		// services which could not be checked count as errors, not down
+		{Service: "hooks", Error: "timeout"},
+	}}
+	assert.Equal(&Summary{Total: 4, Alive: 2, Down: 1, Errors: 1, CheckedAt: checkedAt}, report.Summary())
+	assert.Equal(&Summary{}, (&Report{}).Summary())
+
+	buf := &bytes.Buffer{}
+	printSummary(buf, report.Summary())
+	assert.Equal("2/4 alive, 1 down, 1 error(s)\n", buf.String())
+}
+
+func TestCheckServicesCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	defer func(prefer, allow bool, w io.Writer) {
+		preferHTTPS, allowHTTP, warnings = prefer, allow, w
+	}(preferHTTPS, allowHTTP, warnings)
+	preferHTTPS, allowHTTP, warnings = false, true, ioutil.Discard
+
+	defer func(p PingURLs) { pingURLs = p }(pingURLs)
+	pingURLs = PingURLs{"hanging": server.URL + "/hanging/ping"}
+
+	// as on Ctrl-C during a check
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	report := checkServices(ctx, []string{"hanging"})
+	assert.True(time.Since(start) < requestTimeout, "the check should stop once cancelled")
+	assert.False(report.Services[0].Alive)
+	assert.NotEmpty(report.Services[0].Error)
+
+	cmd := &cobra.Command{}
+	err := checkAndReport(ctx, cmd, []string{"hanging"})
+	assert.Error(err)
+	assert.Equal(ExitFailure, err.(*root.ExitError).Code)
+}
+
+func TestCheckAndReportOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"alive": true, "uptime": 42}`)
+	}))
+	defer server.Close()
+
+	defer func(prefer, allow bool, w io.Writer) {
+		preferHTTPS, allowHTTP, warnings = prefer, allow, w
+	}(preferHTTPS, allowHTTP, warnings)
+	preferHTTPS, allowHTTP, warnings = false, true, ioutil.Discard
+
+	defer func(p PingURLs) { pingURLs = p }(pingURLs)
+	pingURLs = PingURLs{"queue": server.URL + "/queue/ping"}
+
+	dir, err := ioutil.TempDir("", "status-output")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	outputCommand := func(output string) *cobra.Command {
+		cmd := fieldsCommand()
+		cmd.Flags().String("sort-by", "name", "")
+		cmd.Flags().Bool("reverse", false, "")
+		cmd.Flags().String("output", output, "")
+		return cmd
+	}
+
+	report := filepath.Join(dir, "report.txt")
+	assert.NoError(checkAndReport(context.Background(), outputCommand(report), []string{"queue"}))
+	data, err := ioutil.ReadFile(report)
+	assert.NoError(err)
+	assert.Contains(string(data), "queue")
+
+	err = checkAndReport(context.Background(), outputCommand(filepath.Join(dir, "missing", "report.txt")), []string{"queue"})
+	assert.Error(err)
+	assert.Equal(ExitFailure, err.(*root.ExitError).Code, "failing to create the output isn't a usage error")
+}
+
+func TestBenchmarkFailedScrapeKeepsCache(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := &configdir.Config{Path: dir, Type: configdir.Cache}
+	cached := PingURLs{"queue": "https://queue.taskcluster.net/v1/ping"}
+	defer func(q bool) { quietProgress = q }(quietProgress)
+	quietProgress = true
+	_, err = cached.Cache(cache, pingURLsCachePath, defaultManifestURL, false)
+	assert.NoError(err)
+
+	defer func(u string, r bool) { manifestURL, refreshReferences = u, r }(manifestURL, refreshReferences)
+	manifestURL = "file://" + filepath.ToSlash(filepath.Join(dir, "missing.json"))
+	defer func(p PingURLs) { pingURLs = p }(pingURLs)
+
+	assert.Error(benchmarkWithCache(fieldsCommand(), cache))
+	cachedURLs, err := ReadCachedURLsFile(cache, pingURLsCachePath)
+	assert.NoError(err, "a failed scrape should leave the cache alone")
+	assert.Equal(cached, cachedURLs.PingURLs)
+}
+
+func TestPrintBenchmark(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := &bytes.Buffer{}
+	printBenchmark(buf, &Benchmark{
+		Scrape: 1.5,
+		Ping:   0.25,
+		Services: []ServiceStatus{
+			{Service: "queue", Alive: true, Latency: 0.25},
+			{Service: "auth", Latency: 0.1, Error: "boom"},
+		},
+	})
+	assert.Equal("Scrape: 1.500s\n"+
+		"Ping:   0.250s (2 services)\n"+
+		"  queue  0.250s\n"+
+		"  auth   0.100s  error: boom\n", buf.String())
+}
+
+func fieldsCommand(flags ...string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().String("format", "text", "")
+	cmd.Flags().Bool("summary-only", false, "")
+	cmd.Flags().String("compare", "", "")
+	cmd.Flags().Bool("show-changes", false, "")
+	client.AddFieldsFlag(cmd.Flags(), "service,alive")
+	cmd.Flags().Parse(flags)
+	return cmd
+}
+
+func TestRenderFields(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(validateFields(fieldsCommand()))
+	assert.NoError(validateFields(fieldsCommand("--json", "--fields", "service,alive")))
+	assert.NoError(validateFields(fieldsCommand("--json", "--summary-only", "--fields", "down")))
+	assert.Error(validateFields(fieldsCommand("--fields", "service")), "--fields requires JSON")
+	assert.Error(validateFields(fieldsCommand("--json", "--fields", "nope")))
+	assert.Error(validateFields(fieldsCommand("--json", "--summary-only", "--fields", "service")))
+	assert.Error(validateFields(fieldsCommand("--json", "--show-changes", "--fields", "service")))
+
+	report := &Report{Services: []ServiceStatus{{Service: "queue", Alive: true, Latency: 0.25}}}
+	buf := &bytes.Buffer{}
+	assert.NoError(render(fieldsCommand("--json", "--fields", "service,latency"), buf, report))
+	var rendered struct {
+		Services []map[string]interface{} `json:"services"`
+	}
+	assert.NoError(json.Unmarshal(buf.Bytes(), &rendered))
+	assert.Equal([]map[string]interface{}{{"service": "queue", "latency": 0.25}}, rendered.Services)
+}
+
+func TestFieldsFlagExample(t *testing.T) {
+	assert := assert.New(t)
+
+	// the example of the help must name fields of the report
+	statusCmd, _, err := root.Command.Find([]string{"status"})
+	assert.NoError(err)
+	assert.Contains(statusCmd.Flags().Lookup("fields").Usage, "e.g. --fields service,alive.")
+	assert.NoError(validateFields(fieldsCommand("--json", "--fields", "service,alive")))
+}
@@ -0,0 +1,55 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+)
+
+// requiredServices are the services given with --require, validated by
+// preRun; when there are some, only they can make status fail.
+var requiredServices []string
+
+// parseRequire checks that the services given with --require are known.
+func parseRequire(services []string) ([]string, error) {
+	var unknown []string
+	for _, service := range services {
+		if _, ok := pingURLs[service]; !ok {
+			unknown = append(unknown, service)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown service(s) given to --require: %s", strings.Join(unknown, ", "))
+	}
+	return services, nil
+}
+
+// addRequired returns services along with the required services missing from
+// them, so that the required services are always checked.
+func addRequired(services, required []string) []string {
+	seen := make(map[string]bool, len(services))
+	for _, service := range services {
+		seen[service] = true
+	}
+	result := append([]string{}, services...)
+	for _, service := range required {
+		if !seen[service] {
+			seen[service] = true
+			result = append(result, service)
+		}
+	}
+	return result
+}
+
+// isRequired tells whether service can make status fail: all services can,
+// unless some were given with --require.
+func isRequired(service string, required []string) bool {
+	if len(required) == 0 {
+		return true
+	}
+	for _, s := range required {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
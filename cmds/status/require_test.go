@@ -0,0 +1,51 @@
+package status
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestParseRequire(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(p PingURLs) { pingURLs = p }(pingURLs)
+	pingURLs = PingURLs{"queue": "https://queue.taskcluster.net/v1/ping", "auth": "https://auth.taskcluster.net/v1/ping"}
+
+	required, err := parseRequire([]string{"queue"})
+	assert.NoError(err)
+	assert.Equal([]string{"queue"}, required)
+
+	_, err = parseRequire([]string{"queue", "nope"})
+	assert.Error(err)
+	assert.Contains(err.Error(), "nope")
+}
+
+func TestAddRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{"auth", "queue"}, addRequired([]string{"auth"}, []string{"queue", "auth", "queue"}))
+	assert.Equal([]string{"auth"}, addRequired([]string{"auth"}, nil))
+}
+
+func TestReportExitCodeRequired(t *testing.T) {
+	assert := assert.New(t)
+
+	report := &Report{Services: []ServiceStatus{
+		{Service: "auth", Alive: true},
+		{Service: "hooks", Alive: false},
+		{Service: "queue", Alive: true},
+	}}
+
+	code, err := reportExitCode(report, nil)
+	assert.Equal(ExitUnhealthy, code, "without --require any service down fails")
+	assert.Error(err)
+
+	code, err = reportExitCode(report, []string{"auth", "queue"})
+	assert.Equal(ExitHealthy, code, "services which aren't required don't fail")
+	assert.NoError(err)
+
+	code, err = reportExitCode(report, []string{"hooks"})
+	assert.Equal(ExitUnhealthy, code)
+	assert.Contains(err.Error(), "hooks")
+}
@@ -0,0 +1,97 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taskcluster/taskcluster-cli/client"
+)
+
+const (
+	// maxRetries is how many times a rate limited request, or one which
+	// failed with a transient error, is retried
+	maxRetries = 5
+	// initialBackoff is how long to wait before the first retry of a request
+	// which failed with a transient error, or was rate limited without a
+	// Retry-After header; it doubles with each retry
+	initialBackoff = time.Second
+)
+
+// getWithRetries makes a GET request to u with the configured headers. A
+// response with status 429 Too Many Requests is retried after the delay given
+// by its Retry-After header, or after an exponential backoff if it has none,
+// as long as that fits within the deadline of ctx. Requests failing with a
+// transient error are retried after an exponential backoff too, and the
+// error eventually returned is a *requestError telling its class.
+func getWithRetries(ctx context.Context, u string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest("GET", u, nil)
+		if err != nil {
+			return nil, err
+		}
+		for key, values := range headers {
+			for _, v := range values {
+				req.Header.Add(key, v)
+			}
+		}
+
+		resp, err := client.HTTPClient.Do(req.WithContext(ctx))
+		if err != nil {
+			class := classifyError(err)
+			if class == errorPermanent || attempt == maxRetries || sleep(ctx, initialBackoff<<uint(attempt)) != nil {
+				return nil, &requestError{Class: class, Err: err}
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || attempt == maxRetries {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		wait := retryAfter(resp.Header.Get("Retry-After"), time.Now(), initialBackoff<<uint(attempt))
+		if err = sleep(ctx, wait); err != nil {
+			return nil, fmt.Errorf("rate limited by %v: %v", u, err)
+		}
+	}
+}
+
+// retryAfter returns how long to wait according to the value of a Retry-After
+// header, which is either a number of seconds or an HTTP date, relative to
+// now. If the value is missing or invalid, fallback is returned.
+func retryAfter(value string, now time.Time, fallback time.Duration) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return fallback
+}
+
+// sleep waits for d, unless ctx is done first, or its deadline would pass
+// before d elapsed, in which case it fails right away.
+func sleep(ctx context.Context, d time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		return fmt.Errorf("waiting %v to retry would exceed the timeout", d)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
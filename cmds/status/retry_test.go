@@ -0,0 +1,76 @@
+package status
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2017, 4, 11, 9, 0, 0, 0, time.UTC)
+	fallback := 3 * time.Second
+
+	for _, tc := range []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", fallback},
+		{"   ", fallback},
+		{"5", 5 * time.Second},
+		{" 5 ", 5 * time.Second},
+		{"0", 0},
+		{"-5", 0},
+		{"Tue, 11 Apr 2017 09:00:30 GMT", 30 * time.Second},
+		{"Tue, 11 Apr 2017 08:59:00 GMT", 0},
+		{"Tue, 11 Apr 2017 09:00:00 GMT", 0},
+		{"soon", fallback},
+		{"1.5", fallback},
+	} {
+		assert.Equal(t, tc.expected, retryAfter(tc.value, now, fallback), "Retry-After: %q", tc.value)
+	}
+}
+
+func TestSleep(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	err := sleep(ctx, time.Minute)
+	assert.Error(err, "waiting past the deadline should fail")
+	assert.Contains(err.Error(), "would exceed the timeout")
+	assert.True(time.Since(start) < time.Second, "it should fail right away")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(context.Canceled, sleep(ctx, time.Millisecond))
+}
+
+func TestGetWithRetriesRateLimited(t *testing.T) {
+	assert := assert.New(t)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		io.WriteString(w, `{"alive": true}`)
+	}))
+	defer server.Close()
+
+	resp, err := getWithRetries(context.Background(), server.URL)
+	assert.NoError(err)
+	resp.Body.Close()
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(2, attempts, "the rate limited request should be retried once")
+}
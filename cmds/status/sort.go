@@ -0,0 +1,68 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// sortKeys are the keys --sort-by accepts, each with a function telling
+// whether a should be listed before b.
+var sortKeys = map[string]func(a, b ServiceStatus) bool{
+	"name": func(a, b ServiceStatus) bool {
+		return a.Service < b.Service
+	},
+	"uptime": func(a, b ServiceStatus) bool {
+		return a.Uptime < b.Uptime
+	},
+	"latency": func(a, b ServiceStatus) bool {
+		return a.Latency < b.Latency
+	},
+	"status": func(a, b ServiceStatus) bool {
+		return statusRank(a) < statusRank(b)
+	},
+}
+
+// statusRank orders services by health: alive, then down, then in error.
+func statusRank(s ServiceStatus) int {
+	switch {
+	case s.Error != "":
+		return 2
+	case s.Alive:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func sortKeyList() []string {
+	keys := make([]string, 0, len(sortKeys))
+	for key := range sortKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortServices sorts services in ascending order of key, or descending order
+// if reverse is set. Services which compare equal are kept in name order.
+func sortServices(services []ServiceStatus, key string, reverse bool) error {
+	less, ok := sortKeys[key]
+	if !ok {
+		return fmt.Errorf("invalid sort key '%s', expected one of %s", key, strings.Join(sortKeyList(), ", "))
+	}
+	sort.SliceStable(services, func(i, j int) bool {
+		a, b := services[i], services[j]
+		if reverse {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return sortKeys["name"](services[i], services[j])
+	})
+	return nil
+}
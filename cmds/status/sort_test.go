@@ -0,0 +1,43 @@
+package status
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestSortServices(t *testing.T) {
+	services := []ServiceStatus{
+		{Service: "queue", Alive: true, Uptime: 10, Latency: 0.2},
+		{Service: "auth", Alive: true, Uptime: 30, Latency: 0.2},
+		{Service: "index", Error: "timeout", Latency: 0.5},
+		{Service: "hooks", Uptime: 0, Latency: 0.1},
+		{Service: "secrets", Alive: true, Uptime: 30, Latency: 0.3},
+	}
+
+	for _, tc := range []struct {
+		key      string
+		reverse  bool
+		expected []string
+	}{
+		{"name", false, []string{"auth", "hooks", "index", "queue", "secrets"}},
+		{"name", true, []string{"secrets", "queue", "index", "hooks", "auth"}},
+		{"uptime", false, []string{"hooks", "index", "queue", "auth", "secrets"}},
+		// ties are kept in name order, even when reversed
+		{"uptime", true, []string{"auth", "secrets", "queue", "hooks", "index"}},
+		{"latency", false, []string{"hooks", "auth", "queue", "secrets", "index"}},
+		{"latency", true, []string{"index", "secrets", "auth", "queue", "hooks"}},
+		{"status", false, []string{"auth", "queue", "secrets", "hooks", "index"}},
+		{"status", true, []string{"index", "hooks", "auth", "queue", "secrets"}},
+	} {
+		sorted := append([]ServiceStatus(nil), services...)
+		assert.NoError(t, sortServices(sorted, tc.key, tc.reverse))
+		names := make([]string, len(sorted))
+		for i, s := range sorted {
+			names[i] = s.Service
+		}
+		assert.Equal(t, tc.expected, names, "--sort-by %s, reverse: %v", tc.key, tc.reverse)
+	}
+
+	assert.Error(t, sortServices(services, "nope", false))
+}
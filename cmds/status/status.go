@@ -1,30 +1,68 @@
 package status
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/taskcluster/taskcluster-cli/client"
 	"github.com/taskcluster/taskcluster-cli/cmds/root"
+	"github.com/taskcluster/taskcluster-cli/timeparse"
 
 	"github.com/shibukawa/configdir"
 	"github.com/spf13/cobra"
 )
 
 const (
-	manifestURL = "https://references.taskcluster.net/manifest.json"
+	defaultManifestURL = "https://references.taskcluster.net/manifest.json"
+
+	// defaultMaxBodySize is the default value of maxBodySize, 4MiB
+	defaultMaxBodySize = 4 << 20
+
+	// defaultRequestTimeout is the default value of requestTimeout
+	defaultRequestTimeout = time.Minute
+
+	// cacheTTL is how long the cached ping URLs are used before they are
+	// scraped again
+	cacheTTL = 24 * time.Hour
+	// partialCacheTTL is how long ping URLs are cached when some API
+	// references could not be scraped, so that they are retried soon
+	partialCacheTTL = time.Hour
 )
 
 var (
-	pingURLs          PingURLs
-	validArgs         []string
-	cache             = Cache()
+	// pingURLs is loaded by preRun, so that only the status command waits
+	// on the cache or the network
+	pingURLs  PingURLs
+	validArgs []string
+	// manifestURL lists the API references the ping URLs are scraped from;
+	// it can be a file:// URL, set with --manifest-url
+	manifestURL       = defaultManifestURL
 	pingURLsCachePath = filepath.Join("cmds", "status", "pingURLs.json")
+	// lastReportCachePath is where the last report is saved for --show-changes
+	lastReportCachePath = filepath.Join("cmds", "status", "lastReport.json")
+
+	// headers are added to every request made by objectFromJSONURL
+	headers = http.Header{}
+	// maxBodySize is the size in bytes above which objectFromJSONURL gives
+	// up on reading a response
+	maxBodySize int64 = defaultMaxBodySize
+	// requestTimeout bounds the time objectFromJSONURL spends on a URL,
+	// including waiting out rate limits
+	requestTimeout = defaultRequestTimeout
 )
 
 type (
@@ -35,7 +73,14 @@ type (
 	// caching the ping urls (see above)
 	CachedURLs struct {
 		LastUpdated time.Time `json:"lastUpdated"`
-		PingURLs    PingURLs  `json:"pingURLs"`
+		// ManifestURL is the manifest the ping URLs were scraped from; it is
+		// empty in caches written before it was recorded, which all used
+		// the default manifest
+		ManifestURL string `json:"manifestUrl,omitempty"`
+		// Partial is set when some API references could not be scraped, in
+		// which case the cache expires after partialCacheTTL
+		Partial  bool     `json:"partial,omitempty"`
+		PingURLs PingURLs `json:"pingURLs"`
 	}
 
 	// PingResponse defines the data format of the http response from the ping url endpoints
@@ -67,43 +112,93 @@ type (
 	}
 )
 
-// Cache returns the file system path to the cache file storing the ping URLs
-func Cache() (cache *configdir.Config) {
-	configDirs := configdir.New("taskcluster", "taskcluster-cli")
-	cache = configDirs.QueryCacheFolder()
-	return
+var (
+	cacheOnce sync.Once
+	cache     *configdir.Config
+	cacheErr  error
+)
+
+// Cache returns the folder storing the cache files, such as the ping URLs,
+// creating it on first use. It returns an error, rather than panicking, when
+// the folder can't be created, e.g. because $HOME is read-only.
+func Cache() (*configdir.Config, error) {
+	cacheOnce.Do(func() {
+		cache, cacheErr = openCache(cacheFolder())
+	})
+	return cache, cacheErr
 }
 
-func init() {
-	var err error
-	pingURLs, err = NewPingURLs()
-	if err != nil {
-		panic(err)
-	}
+// cacheFolder returns the folder storing the cache files, which may not exist.
+func cacheFolder() *configdir.Config {
+	return configdir.New("taskcluster", "taskcluster-cli").QueryCacheFolder()
+}
 
-	validArgs = make([]string, len(pingURLs))
-	i := 0
-	for k := range pingURLs {
-		validArgs[i] = k
-		i++
-	}
-	use := "status"
-	for _, validArg := range validArgs {
-		use = use + " [" + validArg + "]"
+// openCache makes sure the folder of cache exists.
+func openCache(cache *configdir.Config) (*configdir.Config, error) {
+	if err := cache.MkdirAll(); err != nil {
+		return nil, fmt.Errorf("could not create the cache folder %s: %v", cache.Path, err)
 	}
+	return cache, nil
+}
+
+func init() {
 	statusCmd := &cobra.Command{
 		Short: "status queries the current running status of taskcluster services",
 		Long: `When called without arguments, taskcluster status will return the current running
 status of all production taskcluster services.
 
 By specifying one or more optional services as arguments, you can limit the
-services included in the status report.`,
-		PreRunE:            preRun,
-		Use:                use,
-		ValidArgs:          validArgs,
-		RunE:               status,
-		DisableFlagParsing: true,
+services included in the status report.
+
+The exit code is 0 if all services are healthy, 1 if some are unhealthy or went
+down since the report given to --compare, 2 on usage errors, and 3 if no
+service could be checked at all. Use --exit-code-map to change them, e.g.
+--exit-code-map unhealthy=7. With --require, only the given services are
+deemed unhealthy when down, e.g. to only block a pipeline on the queue and
+auth services:
+
+  taskcluster status --require queue,auth`,
+		PreRunE: preRun,
+		Use:     "status [<service>...]",
+		// completion only reads the cache, it must not wait on the network
+		ValidArgs: cachedServices(),
+		RunE:      status,
 	}
+	statusCmd.Flags().Bool("json", false, "Output the status report as JSON, same as --format json.")
+	statusCmd.Flags().String("format", "text", "Output format, one of text, json, template, html, junit.")
+	client.AddFieldsFlag(statusCmd.Flags(), "service,alive")
+	statusCmd.Flags().StringP("output", "o", "", "Write the report to this file instead of stdout; not with --compare.")
+	statusCmd.Flags().String("template", "", "Go text/template rendering each service result, or the summary with --summary-only, for --format template.")
+	statusCmd.Flags().BoolP("interactive", "i", false, "Prompt for the services to check, when none are given and the terminal allows it.")
+	statusCmd.Flags().Bool("refresh", false, "Scrape the ping URLs again, even if the cached ones haven't expired.")
+	statusCmd.Flags().Bool("watch", false, "Check the services over and over, every --interval, until interrupted.")
+	timeparse.AddDurationFlag(statusCmd.Flags(), "interval", time.Minute, "How long to wait between the checks of --watch, counted from the end of the previous check.")
+	statusCmd.Flags().Duration("interval-jitter", 0, "Add a random delay of up to this long to every --interval, to spread out many watchers.")
+	statusCmd.Flags().StringSlice("require", nil, "Only fail if one of these services is down, while still checking and reporting all of them.")
+	statusCmd.Flags().String("exclude", "", "Skip the services whose name matches this regular expression, e.g. 'secrets|hooks'.")
+	statusCmd.Flags().String("sort-by", "name", "Sort the services by one of "+strings.Join(sortKeyList(), ", ")+".")
+	statusCmd.Flags().Bool("reverse", false, "Reverse the order given by --sort-by.")
+	statusCmd.Flags().Bool("summary-only", false, "Only output the number of services alive, down and in error.")
+	statusCmd.Flags().Bool("show-changes", false, "Show the services whose status changed since the last run with --show-changes.")
+	statusCmd.Flags().String("compare", "", "Compare against a status report previously saved with --json, and fail if a service went down; the changes are printed as text, or as JSON with --json or --format json.")
+	statusCmd.Flags().StringArrayP("header", "H", nil, "Add a custom HTTP header to every request (repeatable) (format: 'Key: Value')")
+	statusCmd.Flags().StringArray("baseurl-override", nil, "Ping a service at another base URL than the scraped one (repeatable) (format: 'service=url', e.g. 'queue=https://queue.staging.example.com/v1')")
+	statusCmd.Flags().Int64("max-body-size", defaultMaxBodySize, "Fail on responses larger than this many bytes.")
+	timeparse.AddDurationFlag(statusCmd.Flags(), "timeout", defaultRequestTimeout, "Give up on a service after this long, including time spent waiting out rate limits.")
+	statusCmd.Flags().StringSlice("exit-code-map", nil, "Remap exit codes (repeatable) (format: 'name=code', with name one of "+strings.Join(exitCodeNameList(), ", ")+")")
+	statusCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &root.ExitError{Code: ExitUsage, Err: &root.UsageError{Err: err}}
+	})
+
+	statusCmd.PersistentFlags().BoolVar(&preferHTTPS, "prefer-https", true, "Ping the services over https even if their base URL is http.")
+	statusCmd.PersistentFlags().BoolVar(&allowHTTP, "insecure-allow-http", false, "Allow pinging services over plain http, with --prefer-https=false.")
+	statusCmd.PersistentFlags().BoolVar(&quietProgress, "quiet-progress", false, "Don't report scraping the ping URLs and writing the cache; these messages go to stderr.")
+	statusCmd.PersistentFlags().BoolVar(&refreshReferences, "refresh-references", false, "Fetch the API references again when scraping the ping URLs, rather than reusing those cached in the last week.")
+	statusCmd.PersistentFlags().StringVar(&manifestURL, "manifest-url", defaultManifestURL, "URL of the manifest of API references to scrape the ping URLs from; file:// URLs are read from disk.")
+
+	statusCmd.AddCommand(cacheCommand())
+	statusCmd.AddCommand(benchmarkCommand())
+	statusCmd.AddCommand(dumpManifestCommand())
 
 	// Add the task subtree to the root.
 	root.Command.AddCommand(statusCmd)
@@ -112,16 +207,28 @@ services included in the status report.`,
 // NewPingURLs returns the ping URLs to use. The caller does not need to be
 // concerned about whether these URLs are retrieved from a local cache, or from
 // querying web services.
-func NewPingURLs() (pingURLs PingURLs, err error) {
+//
+// The ping URLs are loaded once per process and manifest URL, see
+// memoizedPingURLs; callers must not modify them.
+func NewPingURLs() (PingURLs, error) {
+	return memo.get(manifestURL, loadPingURLs)
+}
+
+// loadPingURLs is NewPingURLs without the memoization.
+func loadPingURLs() (pingURLs PingURLs, err error) {
+	cache, err := Cache()
+	if err != nil {
+		return
+	}
 	if !cache.Exists(pingURLsCachePath) {
-		return RefreshCache(manifestURL, cache, pingURLsCachePath)
+		return refreshCache(manifestURL, cache, pingURLsCachePath)
 	}
 	cachedURLs, err := ReadCachedURLsFile(cache, pingURLsCachePath)
 	if err != nil {
 		return
 	}
-	if cachedURLs.Expired(time.Hour * 24) {
-		return RefreshCache(manifestURL, cache, pingURLsCachePath)
+	if cachedURLs.Expired(cachedURLs.TTL()) || cachedURLs.Manifest() != manifestURL {
+		return refreshCache(manifestURL, cache, pingURLsCachePath)
 	}
 	pingURLs = cachedURLs.PingURLs
 	return
@@ -129,13 +236,41 @@ func NewPingURLs() (pingURLs PingURLs, err error) {
 
 // RefreshCache will scrape the manifest url for a dictionary of taskcluster
 // services, and cache the results in file at path.
+//
+// When only some API references could be scraped, the ping URLs of the others
+// are still cached, marked as partial, and returned along with a
+// *ScrapeError; nothing is cached if no ping URL could be scraped at all.
 func RefreshCache(manifestURL string, cache *configdir.Config, cachePath string) (pingURLs PingURLs, err error) {
-	pingURLs, err = ScrapePingURLs(manifestURL)
+	references := openReferenceCache(cache)
+	pingURLs, err = scrapePingURLs(manifestURL, references)
+	references.save()
+	if len(pingURLs) == 0 {
+		switch err.(type) {
+		case nil:
+			err = fmt.Errorf("no pingable services found for manifest %v", manifestURL)
+		case *ScrapeError:
+			// not a partial result, as there is no result at all
+			err = fmt.Errorf("no ping URL could be scraped from %v: %v", manifestURL, err)
+		}
+		return nil, err
+	}
+	scrapeErr := err
+	cachedURLs, err := pingURLs.Cache(cache, cachePath, manifestURL, scrapeErr != nil)
 	if err != nil {
-		return
+		return nil, err
+	}
+	return cachedURLs.PingURLs, scrapeErr
+}
+
+// refreshCache is RefreshCache for callers which can make do with partial
+// ping URLs, of which it warns.
+func refreshCache(manifestURL string, cache *configdir.Config, cachePath string) (PingURLs, error) {
+	pingURLs, err := RefreshCache(manifestURL, cache, cachePath)
+	if scrapeErr, ok := err.(*ScrapeError); ok {
+		color.New(color.FgYellow).Fprintf(warnings, "WARNING: %v; the other services are cached until %v\n", scrapeErr, time.Now().Add(partialCacheTTL).Format(time.Kitchen))
+		return pingURLs, nil
 	}
-	cachedURLs, err := pingURLs.Cache(cache, cachePath)
-	return cachedURLs.PingURLs, err
+	return pingURLs, err
 }
 
 // ReadCachedURLsFile returns a *CachedURLs based on the contents of the file
@@ -150,14 +285,17 @@ func ReadCachedURLsFile(cache *configdir.Config, cachePath string) (cachedURLs *
 	return
 }
 
-// Cache writes the pingURLs p to a file at path (replacing if it exists
-// already, and creating parent folders, if required), using the current time
-// for the retrieval timestamp.
-func (p PingURLs) Cache(cache *configdir.Config, cachePath string) (cachedURLs *CachedURLs, err error) {
-	color.Magenta("Writing cache file %v", filepath.Join(cache.Path, cachePath))
+// Cache writes the pingURLs p, scraped from manifestURL, to a file at path
+// (replacing if it exists already, and creating parent folders, if required),
+// using the current time for the retrieval timestamp. partial tells whether
+// some API references could not be scraped.
+func (p PingURLs) Cache(cache *configdir.Config, cachePath, manifestURL string, partial bool) (cachedURLs *CachedURLs, err error) {
+	reportProgress(color.FgMagenta, "Writing cache file %v", filepath.Join(cache.Path, cachePath))
 
 	cachedURLs = &CachedURLs{
 		LastUpdated: time.Now(),
+		ManifestURL: manifestURL,
+		Partial:     partial,
 		PingURLs:    p,
 	}
 	var bytes []byte
@@ -169,6 +307,43 @@ func (p PingURLs) Cache(cache *configdir.Config, cachePath string) (cachedURLs *
 	return
 }
 
+// Services returns the names of the services of p, sorted.
+func (p PingURLs) Services() []string {
+	services := make([]string, 0, len(p))
+	for service := range p {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// cachedServices returns the names of the services in the ping URLs cache,
+// whether or not it has expired, or nil if there is no cache yet. It never
+// scrapes the manifest, nor creates the cache folder.
+func cachedServices() []string {
+	cachedURLs, err := ReadCachedURLsFile(cacheFolder(), pingURLsCachePath)
+	if err != nil {
+		return nil
+	}
+	return cachedURLs.PingURLs.Services()
+}
+
+// Manifest returns the URL of the manifest the ping URLs were scraped from.
+func (cachedURLs *CachedURLs) Manifest() string {
+	if cachedURLs.ManifestURL == "" {
+		return defaultManifestURL
+	}
+	return cachedURLs.ManifestURL
+}
+
+// TTL returns how long the cached ping URLs are valid.
+func (cachedURLs *CachedURLs) TTL() time.Duration {
+	if cachedURLs.Partial {
+		return partialCacheTTL
+	}
+	return cacheTTL
+}
+
 // Expired checks if the time since the ping urls were cached is more than the
 // specified duration
 func (cachedURLs *CachedURLs) Expired(d time.Duration) bool {
@@ -176,48 +351,171 @@ func (cachedURLs *CachedURLs) Expired(d time.Duration) bool {
 }
 
 func preRun(cmd *cobra.Command, args []string) error {
-	return validateArgs(cmd, args)
+	mappings, err := cmd.Flags().GetStringSlice("exit-code-map")
+	if err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if exitCodeMap, err = parseExitCodeMap(mappings); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if refresh, _ := cmd.Flags().GetBool("refresh"); refresh {
+		pingURLs, err = RefreshPingURLs()
+	} else {
+		pingURLs, err = NewPingURLs()
+	}
+	if err != nil {
+		return exit(cmd, ExitFailure, fmt.Errorf("could not get the ping URLs of the services: %v", err))
+	}
+	if len(pingURLs) == 0 {
+		// checking nothing would look healthy
+		return exit(cmd, ExitFailure, fmt.Errorf("no pingable services found for manifest %v", manifestURL))
+	}
+	values, err := cmd.Flags().GetStringArray("baseurl-override")
+	if err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	overrides, err := parseBaseURLOverrides(values, pingURLs)
+	if err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	pingURLs = pingURLs.override(overrides)
+	validArgs = pingURLs.Services()
+	values, err = cmd.Flags().GetStringArray("header")
+	if err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if headers, err = parseHeaders(values); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if maxBodySize, err = cmd.Flags().GetInt64("max-body-size"); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if maxBodySize <= 0 {
+		return exit(cmd, ExitUsage, fmt.Errorf("--max-body-size must be positive, got %d", maxBodySize))
+	}
+	if requestTimeout, err = cmd.Flags().GetDuration("timeout"); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if requestTimeout <= 0 {
+		return exit(cmd, ExitUsage, fmt.Errorf("--timeout must be positive, got %v", requestTimeout))
+	}
+	if sortBy, _ := cmd.Flags().GetString("sort-by"); sortKeys[sortBy] == nil {
+		return exit(cmd, ExitUsage, fmt.Errorf("invalid --sort-by '%s', expected one of %s", sortBy, strings.Join(sortKeyList(), ", ")))
+	}
+	pattern, _ := cmd.Flags().GetString("exclude")
+	if excludePattern, err = parseExclude(pattern); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if outputTemplate, err = parseTemplate(cmd); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if err = validateCompare(cmd); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if err = validateArgs(args); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if err = validateFields(cmd); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	if err = parseWatch(cmd); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	required, _ := cmd.Flags().GetStringSlice("require")
+	if requiredServices, err = parseRequire(required); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+	return nil
+}
+
+// parseHeaders parses a list of headers of the form 'Key: Value' into an
+// http.Header, rejecting malformed entries.
+func parseHeaders(values []string) (http.Header, error) {
+	h := http.Header{}
+	for _, v := range values {
+		p := strings.SplitN(v, ":", 2)
+		if len(p) != 2 {
+			return nil, fmt.Errorf("invalid header '%s', headers must be on the form 'Key: Value'", v)
+		}
+		key := strings.TrimSpace(p[0])
+		if key == "" || strings.ContainsAny(key, " \t\r\n") {
+			return nil, fmt.Errorf("invalid header name '%s'", p[0])
+		}
+		h.Add(key, strings.TrimSpace(p[1]))
+	}
+	return h, nil
 }
 
 // ScrapePingURLs queries manifestURL to return a manifest of services, which
-// are then queried to fetch ping URLs for taskcluster services
+// are then queried to fetch ping URLs for taskcluster services.
+//
+// API references which can't be scraped are skipped: the ping URLs of the
+// others are returned along with a *ScrapeError listing the failures.
 func ScrapePingURLs(manifestURL string) (pingURLs PingURLs, err error) {
-	color.Yellow("Scraping ping URLs from %v", manifestURL)
-	var allAPIs map[string]string
-	err = objectFromJSONURL(manifestURL, &allAPIs)
+	return scrapePingURLs(manifestURL, openReferenceCache(nil))
+}
+
+// scrapePingURLs is ScrapePingURLs, getting the API references through
+// references, so that recently fetched ones are reused.
+func scrapePingURLs(manifestURL string, references *referenceCache) (pingURLs PingURLs, err error) {
+	reportProgress(color.FgYellow, "Scraping ping URLs from %v", manifestURL)
+	allAPIs, err := fetchManifest(manifestURL)
 	if err != nil {
 		return
 	}
+	// go through the APIs in order, so that failures are reported in order
+	apis := make([]string, 0, len(allAPIs))
+	for api := range allAPIs {
+		apis = append(apis, api)
+	}
+	sort.Strings(apis)
+
 	pingURLs = map[string]string{}
-	for _, apiURL := range allAPIs {
-		reference := new(API)
-		err = objectFromJSONURL(apiURL, reference)
+	scrapeErr := &ScrapeError{}
+	for _, api := range apis {
+		reference, err := references.reference(allAPIs[api])
 		if err != nil {
-			return
+			scrapeErr.Failures = append(scrapeErr.Failures, fmt.Errorf("%s: %v", api, err))
+			continue
 		}
 
 		// loop through entries to find a /ping endpoint
 		for _, entry := range reference.Entries {
 			if entry.Name == "ping" {
 				// determine hostname
-				var u *url.URL
-				u, err = url.Parse(reference.BaseURL)
+				u, err := url.Parse(reference.BaseURL)
 				if err != nil {
-					return
+					scrapeErr.Failures = append(scrapeErr.Failures, fmt.Errorf("%s: %v", api, err))
+					break
 				}
 				hostname := u.Hostname()
 				service := strings.SplitN(hostname, ".", 2)[0]
-				pingURLs[service] = reference.BaseURL + entry.Route
+				pingURL := reference.BaseURL + entry.Route
+				if preferHTTPS {
+					pingURL = upgradeToHTTPS(pingURL)
+				}
+				pingURLs[service] = pingURL
 				break
 			}
 		}
 	}
+	if len(scrapeErr.Failures) > 0 {
+		err = scrapeErr
+	}
 	return
 }
 
-func objectFromJSONURL(urlReturningJSON string, object interface{}) (err error) {
+// objectFromJSONURL unmarshals the JSON at urlReturningJSON, which may be a
+// file, into object, giving up after requestTimeout or once ctx is done.
+func objectFromJSONURL(ctx context.Context, urlReturningJSON string, object interface{}) (err error) {
+	if path, ok := filePath(urlReturningJSON); ok {
+		return objectFromJSONFile(path, object)
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
 	var resp *http.Response
-	resp, err = http.Get(urlReturningJSON)
+	resp, err = getWithRetries(ctx, urlReturningJSON)
 	if err != nil {
 		return
 	}
@@ -230,48 +528,176 @@ func objectFromJSONURL(urlReturningJSON string, object interface{}) (err error)
 	if resp.StatusCode != 200 {
 		return fmt.Errorf("Bad (!= 200) status code %v from %v", resp.StatusCode, urlReturningJSON)
 	}
-	decoder := json.NewDecoder(resp.Body)
-	err = decoder.Decode(&object)
+	var body []byte
+	// read one byte past the limit, to tell a body of exactly maxBodySize
+	// bytes from a larger one
+	body, err = ioutil.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		return
+	}
+	if int64(len(body)) > maxBodySize {
+		return fmt.Errorf("response from %v is larger than the maximum of %d bytes, see --max-body-size", urlReturningJSON, maxBodySize)
+	}
+
+	// proxies and misconfigured servers like to answer with an HTML page,
+	// which would otherwise only produce a cryptic "invalid character '<'"
+	contentType := resp.Header.Get("Content-Type")
+	if !isJSONContentType(contentType) || bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return notJSONError(urlReturningJSON, resp, body, "the response is not JSON")
+	}
+	if err = json.Unmarshal(body, &object); err != nil {
+		return notJSONError(urlReturningJSON, resp, body, err.Error())
+	}
 	return
 }
 
-func validateArgs(cmd *cobra.Command, args []string) error {
-outer:
-	for _, arg := range args {
-		for _, validArg := range cmd.ValidArgs {
-			if arg == validArg {
-				continue outer
-			}
-		}
-		return fmt.Errorf("invalid argument(s) passed")
+// filePath returns the path of the file that rawURL points at, if it is a
+// file:// URL.
+func filePath(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "file" {
+		return "", false
 	}
-	return nil
+	return filepath.FromSlash(u.Path), true
 }
 
-func respbody(service string) error {
-	var servstat PingResponse
-	err := objectFromJSONURL(pingURLs[service], &servstat)
+// objectFromJSONFile is the counterpart of objectFromJSONURL for manifests
+// and references saved to disk, e.g. for offline testing.
+func objectFromJSONFile(path string, object interface{}) error {
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	if servstat.Alive {
-		living := "Alive"
-		fmt.Printf("      %v\n", service)
-		color.Green("      %v\n", living)
+	if err = json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("could not parse %v: %v", path, err)
+	}
+	return nil
+}
+
+// isJSONContentType tells whether contentType allows for a JSON body; a
+// missing content type gets the benefit of the doubt.
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// bodySnippetLength is how much of an unexpected body notJSONError includes.
+const bodySnippetLength = 200
+
+// notJSONError describes a response from urlReturningJSON that could not be
+// decoded, with its status, content type and the start of its body.
+func notJSONError(urlReturningJSON string, resp *http.Response, body []byte, reason string) error {
+	snippet := strings.TrimSpace(string(body))
+	if len(snippet) > bodySnippetLength {
+		snippet = snippet[:bodySnippetLength] + "..."
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "none"
 	}
+	return fmt.Errorf("could not decode JSON from %v (%s): status %q, content type %v, body: %q",
+		urlReturningJSON, reason, resp.Status, contentType, snippet)
+}
 
+func validateArgs(args []string) error {
+	for _, arg := range args {
+		if _, ok := pingURLs[arg]; !ok {
+			return fmt.Errorf("invalid argument(s) passed")
+		}
+	}
 	return nil
 }
 
 func status(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
 		args = validArgs
+		// without a terminal to prompt on, all the services are checked
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive && isInteractive() {
+			picked, err := pickServices(stdin, os.Stderr, args)
+			if err != nil {
+				return exit(cmd, ExitUsage, err)
+			}
+			args = picked
+		}
+	}
+	services := addRequired(excludeServices(args, excludePattern), requiredServices)
+	if len(services) == 0 {
+		return exit(cmd, ExitUsage, fmt.Errorf("--exclude excludes every service, there is nothing to check"))
+	}
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		return watchServices(cmd, services)
+	}
+	return checkAndReport(context.Background(), cmd, services)
+}
+
+// checkAndReport checks services and outputs the report, returning the error
+// to exit with. A check cut short by ctx isn't reported.
+func checkAndReport(ctx context.Context, cmd *cobra.Command, services []string) error {
+	report := checkServices(ctx, services)
+	if ctx.Err() != nil {
+		return exit(cmd, ExitFailure, fmt.Errorf("interrupted while checking the services"))
+	}
+	sortBy, _ := cmd.Flags().GetString("sort-by")
+	reverse, _ := cmd.Flags().GetBool("reverse")
+	if err := sortServices(report.Services, sortBy, reverse); err != nil {
+		return exit(cmd, ExitUsage, err)
+	}
+
+	if file, _ := cmd.Flags().GetString("compare"); file != "" {
+		previous, err := ReadReportFile(file)
+		if err != nil {
+			return exit(cmd, ExitUsage, err)
+		}
+		if err = compare(cmd, previous, report); err != nil {
+			return exit(cmd, ExitUnhealthy, err)
+		}
+		return nil
 	}
-	for _, service := range args {
-		err := respbody(service)
+
+	// the output is passed around rather than set on cmd, so that errors
+	// don't end up in the output file
+	if output, _ := cmd.Flags().GetString("output"); output != "" {
+		file, err := os.Create(output)
 		if err != nil {
-			panic(err)
+			return exit(cmd, ExitFailure, fmt.Errorf("failed to create output file '%s', error: %s", output, err))
+		}
+		err = writeReport(cmd, file, report)
+		// a failed close may mean the report was truncated
+		if err2 := file.Close(); err2 != nil && err == nil {
+			return exit(cmd, ExitFailure, fmt.Errorf("failed to write output file '%s', error: %s", output, err2))
 		}
+		if err != nil {
+			return err
+		}
+	} else if err := writeReport(cmd, cmd.OutOrStdout(), report); err != nil {
+		return err
+	}
+	code, err := reportExitCode(report, requiredServices)
+	return exit(cmd, code, err)
+}
+
+// writeReport writes report to out, in the format selected by the flags of
+// cmd, followed by the changes since the previous run with --show-changes.
+func writeReport(cmd *cobra.Command, out io.Writer, report *Report) error {
+	if showChangesFlag, _ := cmd.Flags().GetBool("show-changes"); !showChangesFlag {
+		return render(cmd, out, report)
+	}
+	changes, err := showChanges(report)
+	if err != nil {
+		return err
+	}
+	if format, _ := outputFormat(cmd); format == "json" {
+		return printJSON(out, changes)
+	}
+	if err = render(cmd, out, report); err != nil {
+		return err
 	}
+	printChanges(out, changes)
 	return nil
 }
@@ -0,0 +1,206 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shibukawa/configdir"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestPingURLsServices(t *testing.T) {
+	assert := assert.New(t)
+
+	p := PingURLs{
+		"queue": "https://queue.taskcluster.net/v1/ping",
+		"auth":  "https://auth.taskcluster.net/v1/ping",
+	}
+	assert.Equal([]string{"auth", "queue"}, p.Services())
+	assert.Equal([]string{}, PingURLs{}.Services())
+}
+
+func TestValidateArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(p PingURLs) { pingURLs = p }(pingURLs)
+	pingURLs = PingURLs{"queue": "https://queue.taskcluster.net/v1/ping"}
+
+	assert.NoError(validateArgs(nil))
+	assert.NoError(validateArgs([]string{"queue"}))
+	assert.Error(validateArgs([]string{"queue", "nope"}))
+}
+
+func TestScrapePingURLsFromFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	reference := filepath.Join(dir, "queue.json")
+	assert.NoError(ioutil.WriteFile(reference, []byte(`{
+		"baseUrl": "https://queue.taskcluster.net/v1",
+		"entries": [{"name": "task", "route": "/task/<taskId>"}, {"name": "ping", "route": "/ping"}]
+	}`), 0644))
+	manifest := filepath.Join(dir, "manifest.json")
+	assert.NoError(ioutil.WriteFile(manifest, []byte(`{"Queue": "file://`+filepath.ToSlash(reference)+`"}`), 0644))
+
+	p, err := ScrapePingURLs("file://" + filepath.ToSlash(manifest))
+	assert.NoError(err)
+	assert.Equal(PingURLs{"queue": "https://queue.taskcluster.net/v1/ping"}, p)
+
+	_, err = ScrapePingURLs("file://" + filepath.ToSlash(filepath.Join(dir, "missing.json")))
+	assert.Error(err)
+}
+
+func TestCachedURLsManifest(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(defaultManifestURL, (&CachedURLs{}).Manifest())
+	assert.Equal("file:///tmp/manifest.json", (&CachedURLs{ManifestURL: "file:///tmp/manifest.json"}).Manifest())
+}
+
+func TestRefreshCachePartial(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	reference := filepath.Join(dir, "queue.json")
+	assert.NoError(ioutil.WriteFile(reference, []byte(`{
+		"baseUrl": "https://queue.taskcluster.net/v1",
+		"entries": [{"name": "ping", "route": "/ping"}]
+	}`), 0644))
+	manifest := filepath.Join(dir, "manifest.json")
+	assert.NoError(ioutil.WriteFile(manifest, []byte(`{
+		"Queue": "file://`+filepath.ToSlash(reference)+`",
+		"Broken": "file://`+filepath.ToSlash(filepath.Join(dir, "missing.json"))+`"
+	}`), 0644))
+	manifestURL := "file://" + filepath.ToSlash(manifest)
+	cache := &configdir.Config{Path: filepath.Join(dir, "cache"), Type: configdir.Cache}
+
+	p, err := RefreshCache(manifestURL, cache, pingURLsCachePath)
+	assert.Equal(PingURLs{"queue": "https://queue.taskcluster.net/v1/ping"}, p, "the scraped ping URLs should be returned")
+	scrapeErr, ok := err.(*ScrapeError)
+	assert.True(ok, "expected a *ScrapeError, got %v", err)
+	assert.Len(scrapeErr.Failures, 1)
+	assert.Contains(scrapeErr.Error(), "Broken")
+
+	cachedURLs, err := ReadCachedURLsFile(cache, pingURLsCachePath)
+	assert.NoError(err, "the partial ping URLs should be cached")
+	assert.True(cachedURLs.Partial)
+	assert.Equal(partialCacheTTL, cachedURLs.TTL())
+	assert.Equal(p, cachedURLs.PingURLs)
+
+	defer func(w io.Writer) { warnings = w }(warnings)
+	buf := &bytes.Buffer{}
+	warnings = buf
+	p, err = refreshCache(manifestURL, cache, pingURLsCachePath)
+	assert.NoError(err, "partial ping URLs should only be warned of")
+	assert.Len(p, 1)
+	assert.Contains(buf.String(), "could not scrape 1 API references")
+}
+
+func TestRefreshCacheNothingScraped(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	manifest := filepath.Join(dir, "manifest.json")
+	assert.NoError(ioutil.WriteFile(manifest, []byte(`{"Broken": "file://`+filepath.ToSlash(filepath.Join(dir, "missing.json"))+`"}`), 0644))
+	cache := &configdir.Config{Path: filepath.Join(dir, "cache"), Type: configdir.Cache}
+
+	_, err = refreshCache("file://"+filepath.ToSlash(manifest), cache, pingURLsCachePath)
+	assert.Error(err)
+	assert.False(cache.Exists(pingURLsCachePath), "nothing should be cached")
+}
+
+func TestRefreshCacheNoPingableServices(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	reference := filepath.Join(dir, "queue.json")
+	assert.NoError(ioutil.WriteFile(reference, []byte(`{
+		"baseUrl": "https://queue.taskcluster.net/v1",
+		"entries": [{"name": "task", "route": "/task/<taskId>"}]
+	}`), 0644))
+	manifest := filepath.Join(dir, "manifest.json")
+	assert.NoError(ioutil.WriteFile(manifest, []byte(`{"Queue": "file://`+filepath.ToSlash(reference)+`"}`), 0644))
+	manifestURL := "file://" + filepath.ToSlash(manifest)
+	cache := &configdir.Config{Path: filepath.Join(dir, "cache"), Type: configdir.Cache}
+
+	_, err = RefreshCache(manifestURL, cache, pingURLsCachePath)
+	assert.EqualError(err, "no pingable services found for manifest "+manifestURL)
+	assert.False(cache.Exists(pingURLsCachePath), "nothing should be cached")
+}
+
+func TestParseHeaders(t *testing.T) {
+	assert := assert.New(t)
+
+	h, err := parseHeaders(nil)
+	assert.NoError(err)
+	assert.Empty(h)
+
+	h, err = parseHeaders([]string{"X-Test: a", "x-test:b", "Authorization:  Bearer token:with:colons "})
+	assert.NoError(err)
+	assert.Equal([]string{"a", "b"}, h["X-Test"], "repeated headers are all kept")
+	assert.Equal("Bearer token:with:colons", h.Get("Authorization"))
+
+	for _, v := range []string{"no colon", ": value", "Bad Name: value", "\t: value"} {
+		_, err = parseHeaders([]string{v})
+		assert.Error(err, "'%s' should be rejected", v)
+	}
+}
+
+func TestIsJSONContentType(t *testing.T) {
+	for contentType, expected := range map[string]bool{
+		"":                                true,
+		"application/json":                true,
+		"application/json; charset=utf-8": true,
+		"Application/JSON":                true,
+		"application/problem+json":        true,
+		"text/html":                       false,
+		"text/plain; charset=utf-8":       false,
+		"application/jsonp":               false,
+		"not a media type;;":              false,
+	} {
+		assert.Equal(t, expected, isJSONContentType(contentType), "Content-Type: %q", contentType)
+	}
+}
+
+func TestObjectFromJSONURLMaxBodySize(t *testing.T) {
+	assert := assert.New(t)
+
+	body := `{"alive": true, "uptime": 42}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	defer func(size int64) { maxBodySize = size }(maxBodySize)
+
+	// a body of exactly the maximum size is accepted
+	maxBodySize = int64(len(body))
+	var ping PingResponse
+	assert.NoError(objectFromJSONURL(context.Background(), server.URL, &ping))
+	assert.True(ping.Alive)
+
+	maxBodySize = int64(len(body)) - 1
+	err := objectFromJSONURL(context.Background(), server.URL, &ping)
+	assert.Error(err)
+	assert.Contains(err.Error(), "see --max-body-size")
+}
@@ -0,0 +1,75 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+)
+
+var (
+	// watchInterval and watchJitter are the --interval and --interval-jitter
+	// of --watch, validated by parseWatch
+	watchInterval time.Duration
+	watchJitter   time.Duration
+	// jitter draws the random part of the delay between checks; each process
+	// seeds its own, so that a fleet of watchers spreads out
+	jitter = rand.New(rand.NewSource(time.Now().UnixNano()))
+	// watchErrors is where the failures of checks are reported with --watch,
+	// which keeps going
+	watchErrors io.Writer = os.Stderr
+)
+
+// parseWatch validates the flags of --watch.
+func parseWatch(cmd *cobra.Command) error {
+	watchInterval, _ = cmd.Flags().GetDuration("interval")
+	watchJitter, _ = cmd.Flags().GetDuration("interval-jitter")
+	if watchInterval <= 0 {
+		return fmt.Errorf("--interval must be positive, got %v", watchInterval)
+	}
+	if watchJitter < 0 || watchJitter > watchInterval {
+		return fmt.Errorf("--interval-jitter must be between 0 and --interval (%v), got %v", watchInterval, watchJitter)
+	}
+	return nil
+}
+
+// watchDelay returns how long to wait after a check before the next one:
+// interval, plus a random part of jitter.
+func watchDelay(interval, jitterWindow time.Duration, r *rand.Rand) time.Duration {
+	if jitterWindow <= 0 {
+		return interval
+	}
+	return interval + time.Duration(r.Int63n(int64(jitterWindow)+1))
+}
+
+// watchServices checks services and outputs the report over and over, until
+// interrupted. The next check is scheduled once the previous one finished, so
+// that slow checks never overlap. Failing checks are reported and don't stop
+// the watch, only usage errors do.
+func watchServices(cmd *cobra.Command, services []string) error {
+	ctx, stop := client.InterruptContext(context.Background())
+	defer stop()
+	for {
+		err := checkAndReport(ctx, cmd, services)
+		if ctx.Err() != nil {
+			// interrupted during the check
+			return err
+		}
+		if exitErr, ok := err.(*root.ExitError); ok && exitErr.Code != ExitUsage {
+			fmt.Fprintf(watchErrors, "%s: %v\n", time.Now().UTC().Format(time.RFC3339), exitErr.Err)
+		} else if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(watchDelay(watchInterval, watchJitter, jitter)):
+		}
+	}
+}
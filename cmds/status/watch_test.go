@@ -0,0 +1,44 @@
+package status
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestWatchDelay(t *testing.T) {
+	assert := assert.New(t)
+
+	r := rand.New(rand.NewSource(1))
+	assert.Equal(time.Minute, watchDelay(time.Minute, 0, r), "no jitter means a fixed delay")
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		d := watchDelay(time.Minute, 10*time.Second, r)
+		assert.True(d >= time.Minute && d <= time.Minute+10*time.Second, "delay %v out of the jitter window", d)
+		seen[d] = true
+	}
+	assert.True(len(seen) > 1, "the jitter should vary")
+}
+
+func TestParseWatch(t *testing.T) {
+	assert := assert.New(t)
+
+	parse := func(interval, jitter string) error {
+		cmd := &cobra.Command{}
+		cmd.Flags().Duration("interval", time.Minute, "")
+		cmd.Flags().Duration("interval-jitter", 0, "")
+		cmd.Flags().Set("interval", interval)
+		cmd.Flags().Set("interval-jitter", jitter)
+		return parseWatch(cmd)
+	}
+
+	assert.NoError(parse("1m", "0s"))
+	assert.NoError(parse("1m", "1m"))
+	assert.Error(parse("0s", "0s"), "the interval must be positive")
+	assert.Error(parse("1m", "-1s"), "the jitter can't be negative")
+	assert.Error(parse("1m", "2m"), "the jitter is capped to the interval")
+}
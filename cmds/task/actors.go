@@ -3,17 +3,23 @@ package task
 import (
 	"fmt"
 	"io"
+	"net/url"
 
 	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 	"github.com/taskcluster/taskcluster-client-go/queue"
 )
 
 // runCancel cancels the runs of a given task.
-func runCancel(credentials *tcclient.Credentials, args []string, out io.Writer, _ *pflag.FlagSet) error {
+func runCancel(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
 	q := makeQueue(credentials)
 	taskID := args[0]
 
+	if dryRun, _ := flagSet.GetBool("dry-run"); dryRun {
+		return client.PrintRequest(out, "POST", q.BaseURL+"/task/"+url.QueryEscape(taskID)+"/cancel", nil)
+	}
+
 	c, err := q.CancelTask(taskID)
 	if err != nil {
 		fmt.Println(err)
@@ -26,10 +32,14 @@ func runCancel(credentials *tcclient.Credentials, args []string, out io.Writer,
 }
 
 // runRerun re-runs a given task.
-func runRerun(credentials *tcclient.Credentials, args []string, out io.Writer, _ *pflag.FlagSet) error {
+func runRerun(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
 	q := makeQueue(credentials)
 	taskID := args[0]
 
+	if dryRun, _ := flagSet.GetBool("dry-run"); dryRun {
+		return client.PrintRequest(out, "POST", q.BaseURL+"/task/"+url.QueryEscape(taskID)+"/rerun", nil)
+	}
+
 	c, err := q.RerunTask(taskID)
 	if err != nil {
 		return fmt.Errorf("could not rerun the task %s: %v", taskID, err)
@@ -41,7 +51,7 @@ func runRerun(credentials *tcclient.Credentials, args []string, out io.Writer, _
 }
 
 // runComplete completes a given task.
-func runComplete(credentials *tcclient.Credentials, args []string, out io.Writer, _ *pflag.FlagSet) error {
+func runComplete(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
 	q := makeQueue(credentials)
 	taskID := args[0]
 
@@ -50,10 +60,23 @@ func runComplete(credentials *tcclient.Credentials, args []string, out io.Writer
 		return fmt.Errorf("could not get the status of the task %s: %v", taskID, err)
 	}
 
-	c, err := q.ClaimTask(taskID, fmt.Sprint(len(s.Status.Runs)-1), &queue.TaskClaimRequest{
+	runID := fmt.Sprint(len(s.Status.Runs) - 1)
+	claim := &queue.TaskClaimRequest{
 		WorkerGroup: s.Status.WorkerType,
 		WorkerID:    "taskcluster-cli",
-	})
+	}
+
+	// the status is only read, so a dry-run can still rely on it to show the
+	// claim and completion requests that would follow
+	if dryRun, _ := flagSet.GetBool("dry-run"); dryRun {
+		runURL := q.BaseURL + "/task/" + url.QueryEscape(taskID) + "/runs/" + url.QueryEscape(runID)
+		if err := client.PrintRequest(out, "POST", runURL+"/claim", claim); err != nil {
+			return err
+		}
+		return client.PrintRequest(out, "POST", runURL+"/completed", nil)
+	}
+
+	c, err := q.ClaimTask(taskID, runID, claim)
 	if err != nil {
 		return fmt.Errorf("could not claim the task %s: %v", taskID, err)
 	}
@@ -94,3 +94,29 @@ func (suite *FakeServerSuite) TestRunCompleteCommand() {
 
 	suite.Equal(string(buf.Bytes()), "completed 'completed'\n")
 }
+
+func (suite *FakeServerSuite) TestRunCancelDryRunCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Bool("dry-run", true, "")
+
+	args := []string{fakeTaskID}
+	suite.NoError(runCancel(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	suite.Equal("POST "+queueBaseURL+"/task/"+fakeTaskID+"/cancel\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestRunCompleteDryRunCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Bool("dry-run", true, "")
+
+	args := []string{fakeTaskID}
+	suite.NoError(runComplete(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+
+	runURL := queueBaseURL + "/task/" + fakeTaskID + "/runs/" + fakeRunID
+	suite.Equal(
+		"POST "+runURL+"/claim\n"+
+			"{\n  \"workerGroup\": \"\",\n  \"workerId\": \"taskcluster-cli\"\n}\n"+
+			"POST "+runURL+"/completed\n",
+		buf.String(),
+	)
+}
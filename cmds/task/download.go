@@ -0,0 +1,119 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/queue"
+)
+
+// signedURLDuration is how long the URLs signed to download private
+// artifacts are valid for.
+const signedURLDuration = 15 * time.Minute
+
+// runDownloadAll downloads the artifacts of a run of a task into a directory,
+// at paths mirroring their names. Reference and error artifacts, which have
+// no content of their own, are skipped.
+func runDownloadAll(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
+	q := makeQueue(credentials)
+	taskID := args[0]
+
+	runID := -1
+	if len(args) > 1 {
+		id, err := strconv.Atoi(args[1])
+		if err != nil || id < 0 {
+			return fmt.Errorf("invalid runId '%s'", args[1])
+		}
+		runID = id
+	}
+	runID, err := resolveRunID(q, taskID, runID)
+	if err != nil {
+		return err
+	}
+
+	dir, _ := flagSet.GetString("output-dir")
+	if dir == "" {
+		dir = taskID
+	}
+
+	artifacts, err := listArtifacts(q, taskID, runID, 0)
+	if err != nil {
+		return err
+	}
+
+	downloaded, skipped, failed := 0, 0, 0
+	// paths maps the path of each downloaded artifact to its name, to catch
+	// names which end up at the same path
+	paths := map[string]string{}
+	for _, a := range artifacts {
+		if a.StorageType == "reference" || a.StorageType == "error" {
+			fmt.Fprintf(out, "skipped %s: %s artifact\n", a.Name, a.StorageType)
+			skipped++
+			continue
+		}
+
+		path, err := artifactPath(dir, a.Name)
+		if other, ok := paths[path]; err == nil && ok {
+			err = fmt.Errorf("its path %s is that of %s already", path, other)
+		}
+		var size int64
+		if err == nil {
+			paths[path] = a.Name
+			size, err = downloadArtifact(credentials, q, taskID, runID, a.Name, path)
+		}
+		if err != nil {
+			fmt.Fprintf(out, "failed  %s: %v\n", a.Name, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "ok      %s (%d bytes)\n", a.Name, size)
+		downloaded++
+	}
+
+	fmt.Fprintf(out, "%d downloaded to %s, %d skipped, %d failed\n", downloaded, dir, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d artifact(s) could not be downloaded", failed)
+	}
+	return nil
+}
+
+// artifactPath returns the path in dir where the artifact name is saved. It
+// fails for names which would end up outside of dir, such as ../x or /x.
+func artifactPath(dir, name string) (string, error) {
+	rel := filepath.FromSlash(name)
+	if rel == "" || filepath.IsAbs(rel) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("unsafe artifact name")
+	}
+	rel = filepath.Clean(rel)
+	if rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("unsafe artifact name")
+	}
+	return filepath.Join(dir, rel), nil
+}
+
+// downloadArtifact saves the content of an artifact to path, creating its
+// parent directories, and returns its size. Interrupted downloads are resumed,
+// see download.
+func downloadArtifact(credentials *tcclient.Credentials, q *queue.Queue, taskID string, runID int, name, path string) (int64, error) {
+	u := q.BaseURL + "/task/" + url.QueryEscape(taskID) + "/runs/" + strconv.Itoa(runID) + "/artifacts/" + url.QueryEscape(name)
+	if credentials != nil {
+		signed, err := q.GetArtifact_SignedURL(taskID, strconv.Itoa(runID), name, signedURLDuration)
+		if err != nil {
+			return 0, fmt.Errorf("could not sign the URL: %v", err)
+		}
+		u = signed.String()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, err
+	}
+	return downloadFile(u, path)
+}
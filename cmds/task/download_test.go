@@ -0,0 +1,47 @@
+package task
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+func (suite *FakeServerSuite) TestDownloadAllCommand() {
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	suite.NoError(err)
+	defer os.RemoveAll(dir)
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().String("output-dir", dir, "")
+
+	args := []string{fakeTaskID, fakeRunID}
+	suite.NoError(runDownloadAll(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+	suite.Equal("skipped fake_live.log: reference artifact\n"+
+		"ok      fake_live_backing.log (12 bytes)\n"+
+		"1 downloaded to "+dir+", 1 skipped, 0 failed\n", buf.String())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "fake_live_backing.log"))
+	suite.NoError(err)
+	suite.Equal("hello world\n", string(data))
+}
+
+func TestArtifactPath(t *testing.T) {
+	assert := assert.New(t)
+
+	path, err := artifactPath("out", "public/logs/live.log")
+	assert.NoError(err)
+	assert.Equal(filepath.Join("out", "public", "logs", "live.log"), path)
+
+	path, err = artifactPath("out", "public/./a//b")
+	assert.NoError(err)
+	assert.Equal(filepath.Join("out", "public", "a", "b"), path)
+
+	for _, name := range []string{"", ".", "..", "../x", "public/../../x", "/etc/passwd"} {
+		_, err = artifactPath("out", name)
+		assert.Error(err, "%q should be rejected", name)
+	}
+}
@@ -2,25 +2,30 @@ package task
 
 import (
 	"bufio"
-	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 
 	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 	"github.com/taskcluster/taskcluster-client-go/queue"
 )
 
-// allow overriding the base URL for testing
+// allow overriding the base URL, with --endpoint or for testing
 var queueBaseURL string
 
 func makeQueue(credentials *tcclient.Credentials) *queue.Queue {
-	q := queue.New(credentials)
-	if queueBaseURL != "" {
-		q.BaseURL = queueBaseURL
-	}
-	return q
+	return clientfactory.New(credentials).WithBaseURL("queue", queueBaseURL).Queue()
+}
+
+// makeContextQueue is makeQueue with a client whose requests are given up
+// once ctx is done.
+func makeContextQueue(ctx context.Context, credentials *tcclient.Credentials) *queue.Queue {
+	f := clientfactory.New(credentials).WithBaseURL("queue", queueBaseURL)
+	f.HTTPClient = &client.ContextClient{Context: ctx, Client: client.HTTPClient}
+	return f.Queue()
 }
 
 // runStatus gets the status of run(s) of a given task.
@@ -28,11 +33,6 @@ func runStatus(credentials *tcclient.Credentials, args []string, out io.Writer,
 	q := makeQueue(credentials)
 	taskID := args[0]
 
-	s, err := q.Status(taskID)
-	if err != nil {
-		return fmt.Errorf("could not get the status of the task %s: %v", taskID, err)
-	}
-
 	allRuns, _ := flagSet.GetBool("all-runs")
 	runID, _ := flagSet.GetInt("run")
 
@@ -40,9 +40,44 @@ func runStatus(credentials *tcclient.Credentials, args []string, out io.Writer,
 		return fmt.Errorf("can't specify both all-runs and a specific run")
 	}
 
+	exitMappings, _ := flagSet.GetStringSlice("exit-on-state")
+	if follow, _ := flagSet.GetBool("follow"); follow {
+		if allRuns || runID != -1 {
+			return fmt.Errorf("can't specify follow with all-runs or a specific run")
+		}
+		interval, _ := flagSet.GetDuration("interval")
+		if interval <= 0 {
+			return fmt.Errorf("interval must be positive, got %v", interval)
+		}
+		exitOnState, err := parseExitOnState(exitMappings)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := client.InterruptContext(context.Background())
+		defer cancel()
+		return followStatus(ctx, makeContextQueue(ctx, credentials), taskID, interval, exitOnState, out)
+	} else if len(exitMappings) > 0 {
+		return fmt.Errorf("--exit-on-state requires --follow")
+	}
+
+	s, err := q.Status(taskID)
+	if err != nil {
+		return fmt.Errorf("could not get the status of the task %s: %v", taskID, err)
+	}
+
 	if allRuns {
 		for _, r := range s.Status.Runs {
 			fmt.Fprintf(out, "Run #%d: %s\n", r.RunID, getRunStatusString(r.State, r.ReasonResolved))
+			fmt.Fprintf(out, "  Reason created:  %s\n", orDash(r.ReasonCreated))
+			fmt.Fprintf(out, "  Reason resolved: %s\n", orDash(r.ReasonResolved))
+			fmt.Fprintf(out, "  Scheduled:       %s\n", formatTime(r.Scheduled))
+			fmt.Fprintf(out, "  Started:         %s\n", formatTime(r.Started))
+			fmt.Fprintf(out, "  Resolved:        %s\n", formatTime(r.Resolved))
+			worker := "-"
+			if r.WorkerGroup != "" || r.WorkerID != "" {
+				worker = r.WorkerGroup + "/" + r.WorkerID
+			}
+			fmt.Fprintf(out, "  Worker:          %s\n", worker)
 		}
 		return nil
 	}
@@ -91,39 +126,65 @@ func runArtifacts(credentials *tcclient.Credentials, args []string, out io.Write
 	q := makeQueue(credentials)
 	taskID := args[0]
 
-	s, err := q.Status(taskID)
+	runID, _ := flagSet.GetInt("run")
+	runID, err := resolveRunID(q, taskID, runID)
 	if err != nil {
-		return fmt.Errorf("could not get the status of the task %s: %v", taskID, err)
+		return err
 	}
 
-	runID, _ := flagSet.GetInt("run")
+	limit, _ := flagSet.GetInt("limit")
+	artifacts, err := listArtifacts(q, taskID, runID, limit)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(artifacts))
+	for _, a := range artifacts {
+		names = append(names, a.Name)
+	}
+	client.PrintList(out, flagSet, names)
+	return nil
+}
+
+// resolveRunID checks that the task has a run runID, and returns it, or the
+// ID of the latest run if runID is -1.
+func resolveRunID(q *queue.Queue, taskID string, runID int) (int, error) {
+	s, err := q.Status(taskID)
+	if err != nil {
+		return 0, fmt.Errorf("could not get the status of the task %s: %v", taskID, err)
+	}
 	if runID >= len(s.Status.Runs) {
-		return fmt.Errorf("there is no run #%v", runID)
+		return 0, fmt.Errorf("there is no run #%v", runID)
 	}
 	if runID == -1 {
 		runID = len(s.Status.Runs) - 1
 	}
+	return runID, nil
+}
 
-	buf := bytes.NewBufferString("")
-	continuation := ""
-	for {
-		a, err := q.ListArtifacts(taskID, fmt.Sprint(runID), continuation, "")
-		if err != nil {
-			return fmt.Errorf("could not fetch artifacts for task %s run %v: %v", taskID, runID, err)
-		}
+// artifact is an entry of the listArtifacts response.
+type artifact struct {
+	Name        string `json:"name"`
+	StorageType string `json:"storageType"`
+	ContentType string `json:"contentType"`
+}
 
-		for _, ar := range a.Artifacts {
-			fmt.Fprintf(buf, "%s\n", ar.Name)
+// listArtifacts returns the artifacts of a run of a task, or the first limit
+// of them if limit is positive.
+func listArtifacts(q *queue.Queue, taskID string, runID, limit int) ([]artifact, error) {
+	artifacts := []artifact{}
+	err := client.Paginate(limit, func(continuationToken string, pageLimit int) (string, int, error) {
+		a, err := q.ListArtifacts(taskID, fmt.Sprint(runID), continuationToken, limitString(pageLimit))
+		if err != nil {
+			return "", 0, fmt.Errorf("could not fetch artifacts for task %s run %v: %v", taskID, runID, err)
 		}
 
-		continuation = a.ContinuationToken
-		if continuation == "" {
-			break
+		page := a.Artifacts[:client.PageSize(len(a.Artifacts), pageLimit)]
+		for _, ar := range page {
+			artifacts = append(artifacts, artifact{Name: ar.Name, StorageType: ar.StorageType, ContentType: ar.ContentType})
 		}
-	}
-
-	buf.WriteTo(out)
-	return nil
+		return a.ContinuationToken, len(page), nil
+	})
+	return artifacts, err
 }
 
 func runLog(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
@@ -142,7 +203,7 @@ func runLog(credentials *tcclient.Credentials, args []string, out io.Writer, fla
 
 	path := "https://queue.taskcluster.net/v1/task/" + taskID + "/artifacts/public/logs/live.log"
 
-	resp, err := http.Get(path)
+	resp, err := client.HTTPClient.Get(path)
 	if err != nil {
 		return fmt.Errorf("Error making request to %v: %v", path, err)
 	}
@@ -2,19 +2,23 @@ package task
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 	assert "github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 )
 
 const fakeTaskID = "ANnmjMocTymeTID0tlNJAw"
 const fakeRunID = "0"
+const fakeFollowTaskID = "fOLLowMocTymeTID0tlNJAw"
 
 type FakeServerSuite struct {
 	suite.Suite
@@ -27,9 +31,13 @@ func (suite *FakeServerSuite) SetupSuite() {
 	handler.HandleFunc("/v1/task/"+fakeTaskID, taskHandler)
 
 	handler.HandleFunc("/v1/task/"+fakeTaskID+"/status", manifestHandler)
+	handler.HandleFunc("/v1/task/"+fakeFollowTaskID+"/status", followStatusHandler())
 	suite.testServer = httptest.NewServer(handler)
 
 	handler.HandleFunc("/v1/task/"+fakeTaskID+"/runs/"+fakeRunID+"/artifacts", artifactsHandler)
+	handler.HandleFunc("/v1/task/"+fakeTaskID+"/runs/"+fakeRunID+"/artifacts/fake_live_backing.log", artifactHandler)
+	handler.HandleFunc("/v1/task/"+fakeTaskID+"/artifacts/fake_live_backing.log", artifactHandler)
+	handler.HandleFunc("/index/v1/task/"+fakeNamespace, indexedTaskHandler)
 
 	handler.HandleFunc("/v1/task/"+fakeTaskID+"/cancel", cancelHandler)
 
@@ -41,11 +49,13 @@ func (suite *FakeServerSuite) SetupSuite() {
 
 	// set the base URL the subcommands use to point to the fake server
 	queueBaseURL = suite.testServer.URL + "/v1"
+	indexBaseURL = suite.testServer.URL + "/index/v1"
 }
 
 func (suite *FakeServerSuite) TearDownSuite() {
 	suite.testServer.Close()
 	queueBaseURL = ""
+	indexBaseURL = ""
 }
 
 func TestFakeServerSuite(t *testing.T) {
@@ -68,7 +78,12 @@ func manifestHandler(w http.ResponseWriter, _ *http.Request) {
 				        "runId": 0,
 				        "state": "completed",
 				        "reasonCreated": "scheduled",
-				        "reasonResolved": "completed"
+				        "reasonResolved": "completed",
+				        "scheduled": "2018-03-01T10:00:00.000Z",
+				        "started": "2018-03-01T10:00:05.000Z",
+				        "resolved": "2018-03-01T10:10:00.000Z",
+				        "workerGroup": "us-east-1",
+				        "workerId": "i-0123456789"
 				      }
 				    ]
 				  }
@@ -76,6 +91,29 @@ func manifestHandler(w http.ResponseWriter, _ *http.Request) {
 	io.WriteString(w, status)
 }
 
+// returns a status that moves from pending to running to failed, with the
+// running state returned twice
+func followStatusHandler() http.HandlerFunc {
+	states := []string{"pending", "running", "running", "failed"}
+	calls := 0
+	return func(w http.ResponseWriter, _ *http.Request) {
+		state := states[calls]
+		if calls < len(states)-1 {
+			calls++
+		}
+		reason := ""
+		if state == "failed" {
+			reason = "failed"
+		}
+		io.WriteString(w, `{
+			"status": {
+				"state": "`+state+`",
+				"runs": [{"runId": 0, "state": "`+state+`", "reasonCreated": "scheduled", "reasonResolved": "`+reason+`"}]
+			}
+		}`)
+	}
+}
+
 func artifactsHandler(w http.ResponseWriter, _ *http.Request) {
 	artifacts := `{
 				  	"artifacts": [
@@ -96,6 +134,11 @@ func artifactsHandler(w http.ResponseWriter, _ *http.Request) {
 	io.WriteString(w, artifacts)
 }
 
+// returns the content of the fake_live_backing.log artifact
+func artifactHandler(w http.ResponseWriter, _ *http.Request) {
+	io.WriteString(w, "hello world\n")
+}
+
 func setUpCommand() (*bytes.Buffer, *cobra.Command) {
 	buf := &bytes.Buffer{}
 	cmd := &cobra.Command{}
@@ -153,6 +196,15 @@ func (suite *FakeServerSuite) TestArtifactsCommand() {
 
 }
 
+func (suite *FakeServerSuite) TestArtifactsCountCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Bool("count", true, "")
+
+	args := []string{fakeTaskID}
+	suite.NoError(runArtifacts(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+	suite.Equal("2\n", buf.String())
+}
+
 func (suite *FakeServerSuite) TestGroupCommand() {
 	// set up to run a command and capture output
 	buf, cmd := setUpCommand()
@@ -185,6 +237,116 @@ func (suite *FakeServerSuite) TestStatusCommand() {
 
 	runStatus(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags())
 
-	suite.Equal(string(buf2.Bytes()), "Run #0: completed 'completed'\n")
+	suite.Equal(string(buf2.Bytes()), "Run #0: completed 'completed'\n"+
+		"  Reason created:  scheduled\n"+
+		"  Reason resolved: completed\n"+
+		"  Scheduled:       2018-03-01T10:00:00Z\n"+
+		"  Started:         2018-03-01T10:00:05Z\n"+
+		"  Resolved:        2018-03-01T10:10:00Z\n"+
+		"  Worker:          us-east-1/i-0123456789\n")
+
+}
+
+func (suite *FakeServerSuite) TestStatusFollowCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Bool("all-runs", false, "")
+	cmd.Flags().Int("run", -1, "")
+	cmd.Flags().Bool("follow", true, "")
+	cmd.Flags().Duration("interval", time.Millisecond, "")
+
+	args := []string{fakeFollowTaskID}
+	err := runStatus(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags())
+
+	suite.Equal(exitFailed, root.ExitCode(err))
+	suite.Equal("Run #0: pending\nRun #0: running\nRun #0: failed 'failed'\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestStatusFollowExitOnStateCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Bool("all-runs", false, "")
+	cmd.Flags().Int("run", -1, "")
+	cmd.Flags().Bool("follow", true, "")
+	cmd.Flags().Duration("interval", time.Millisecond, "")
+	cmd.Flags().StringSlice("exit-on-state", nil, "")
+	cmd.Flags().Set("exit-on-state", "failed=7")
+
+	args := []string{fakeFollowTaskID}
+	err := runStatus(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags())
+
+	suite.Equal(7, root.ExitCode(err))
+	suite.Contains(buf.String(), "failed 'failed'")
+
+	cmd.Flags().Set("exit-on-state", "failed=0")
+	suite.NoError(runStatus(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+}
+
+func TestFollowStatusInterrupted(t *testing.T) {
+	assert := assert.New(t)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	defer func(u string) { queueBaseURL = u }(queueBaseURL)
+	queueBaseURL = server.URL + "/v1"
+
+	// as on Ctrl-C while a request hangs
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err := followStatus(ctx, makeContextQueue(ctx, &tcclient.Credentials{}), fakeFollowTaskID, time.Millisecond, defaultExitOnState, &bytes.Buffer{})
+	assert.Error(err)
+	assert.Contains(err.Error(), "stopped following task")
+	assert.Equal(exitInterrupted, root.ExitCode(err))
+	assert.Equal("interrupted", root.ErrorCode(err))
+	assert.True(time.Since(start) < 5*time.Second, "following should stop once interrupted")
+}
+
+func TestFollowStatusError(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	defer func(u string) { queueBaseURL = u }(queueBaseURL)
+	queueBaseURL = server.URL + "/v1"
+
+	ctx := context.Background()
+	err := followStatus(ctx, makeContextQueue(ctx, &tcclient.Credentials{}), fakeFollowTaskID, time.Millisecond, defaultExitOnState, &bytes.Buffer{})
+	assert.Error(err)
+	assert.Contains(err.Error(), "could not get the status of the task")
+	assert.Equal(exitError, root.ExitCode(err), "errors must not exit like failed tasks")
+	assert.Equal("error", root.ErrorCode(err))
+
+	exitOnState, err := parseExitOnState([]string{"error=0"})
+	assert.NoError(err)
+	err = followStatus(ctx, makeContextQueue(ctx, &tcclient.Credentials{}), fakeFollowTaskID, time.Millisecond, exitOnState, &bytes.Buffer{})
+	assert.Error(err, "the error is still reported")
+	assert.Equal(0, root.ExitCode(err))
+}
 
+func (suite *FakeServerSuite) TestStatusExitOnStateWithoutFollowCommand() {
+	_, cmd := setUpCommand()
+	cmd.Flags().Bool("all-runs", false, "")
+	cmd.Flags().Int("run", -1, "")
+	cmd.Flags().Bool("follow", false, "")
+	cmd.Flags().StringSlice("exit-on-state", nil, "")
+	cmd.Flags().Set("exit-on-state", "failed=7")
+
+	suite.Error(runStatus(&tcclient.Credentials{}, []string{fakeTaskID}, cmd.OutOrStdout(), cmd.Flags()))
+}
+
+func (suite *FakeServerSuite) TestStatusFollowCompletedCommand() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().Bool("all-runs", false, "")
+	cmd.Flags().Int("run", -1, "")
+	cmd.Flags().Bool("follow", true, "")
+	cmd.Flags().Duration("interval", time.Millisecond, "")
+
+	args := []string{fakeTaskID}
+	suite.NoError(runStatus(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+	suite.Equal("Run #0: completed 'completed'\n", buf.String())
 }
@@ -0,0 +1,152 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+	"github.com/taskcluster/taskcluster-client-go/queue"
+)
+
+// Exit codes of task status --follow, depending on how the task resolved, or
+// why following it stopped before that.
+const (
+	exitCompleted = 0
+	exitFailed    = 1
+	exitException = 2
+	// the status of the task could not be polled
+	exitError = 3
+	// following was interrupted with Ctrl-C, as by convention for SIGINT
+	exitInterrupted = 130
+)
+
+// defaultExitOnState maps the states a task resolves to, along with the
+// reasons why following it may stop before that, onto the exit codes of task
+// status --follow; --exit-on-state overrides them.
+var defaultExitOnState = map[string]int{
+	"completed":   exitCompleted,
+	"failed":      exitFailed,
+	"exception":   exitException,
+	"error":       exitError,
+	"interrupted": exitInterrupted,
+}
+
+// resolvedStates are the states a task resolves to, in order.
+var resolvedStates = []string{"completed", "failed", "exception"}
+
+// exitOnStates are the states of defaultExitOnState, in order.
+var exitOnStates = []string{"completed", "failed", "exception", "error", "interrupted"}
+
+// isResolved returns whether a task in state is resolved.
+func isResolved(state string) bool {
+	for _, s := range resolvedStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// parseExitOnState parses entries of the form 'state=code', such as
+// 'exception=1', overriding the exit codes of defaultExitOnState.
+func parseExitOnState(values []string) (map[string]int, error) {
+	m := make(map[string]int, len(defaultExitOnState))
+	for state, code := range defaultExitOnState {
+		m[state] = code
+	}
+	for _, v := range values {
+		p := strings.SplitN(v, "=", 2)
+		if len(p) != 2 {
+			return nil, fmt.Errorf("invalid --exit-on-state mapping '%s', mappings must be on the form 'state=code'", v)
+		}
+		state := strings.TrimSpace(p[0])
+		if _, ok := defaultExitOnState[state]; !ok {
+			return nil, fmt.Errorf("unknown state '%s' in --exit-on-state, expected one of %s", state, strings.Join(exitOnStates, ", "))
+		}
+		code, err := strconv.Atoi(strings.TrimSpace(p[1]))
+		if err != nil || code < 0 || code > 255 {
+			return nil, fmt.Errorf("invalid exit code '%s', exit codes must be between 0 and 255", p[1])
+		}
+		m[state] = code
+	}
+	return m, nil
+}
+
+// resolvedError returns the error to exit with once taskID resolved to state,
+// with the exit code that exitOnState maps state to.
+func resolvedError(taskID, state string, exitOnState map[string]int) error {
+	code := exitOnState[state]
+	if code == 0 {
+		return nil
+	}
+	var err error
+	switch state {
+	case "completed":
+		err = fmt.Errorf("task %s completed", taskID)
+	case "failed":
+		err = fmt.Errorf("task %s failed", taskID)
+	default:
+		err = fmt.Errorf("task %s resolved with an exception", taskID)
+	}
+	return &root.ExitError{Code: code, Err: root.WithCode(state, err)}
+}
+
+// followError returns the error to exit with when following taskID stopped
+// before it resolved, because polling its status failed with err, with the
+// exit code that exitOnState maps interrupted or error to. The error is
+// returned even if that code is 0, so that it is still reported.
+func followError(taskID string, err error, exitOnState map[string]int) error {
+	switch err {
+	case context.Canceled:
+		return &root.ExitError{Code: exitOnState["interrupted"], Err: root.WithCode("interrupted", fmt.Errorf("stopped following task %s: interrupted", taskID))}
+	case context.DeadlineExceeded:
+		err = fmt.Errorf("stopped following task %s: %v", taskID, err)
+	default:
+		err = fmt.Errorf("could not get the status of the task %s: %v", taskID, err)
+	}
+	return &root.ExitError{Code: exitOnState["error"], Err: root.WithCode("error", err)}
+}
+
+// followStatus polls the status of taskID every interval and prints the state
+// of its latest run whenever it changes, until the task is resolved or ctx is
+// done; q should send its requests with ctx, see makeContextQueue. The error
+// returned reflects how the task resolved, with the exit code exitOnState
+// maps its state to, or why following it stopped, see followError.
+func followStatus(ctx context.Context, q *queue.Queue, taskID string, interval time.Duration, exitOnState map[string]int, out io.Writer) error {
+	last := ""
+	for {
+		var s *queue.TaskStatusResponse
+		err := client.CallWithContext(ctx, func() (err error) {
+			s, err = q.Status(taskID)
+			return
+		})
+		if err != nil {
+			return followError(taskID, err, exitOnState)
+		}
+
+		current := s.Status.State
+		if n := len(s.Status.Runs); n > 0 {
+			r := s.Status.Runs[n-1]
+			current = fmt.Sprintf("Run #%d: %s", r.RunID, getRunStatusString(r.State, r.ReasonResolved))
+		}
+		if current != last {
+			fmt.Fprintln(out, current)
+			last = current
+		}
+
+		if isResolved(s.Status.State) {
+			return resolvedError(taskID, s.Status.State, exitOnState)
+		}
+
+		select {
+		case <-ctx.Done():
+			return followError(taskID, ctx.Err(), exitOnState)
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/clientfactory"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/index"
+)
+
+// allow overriding the base URL of the index, for testing
+var indexBaseURL string
+
+func makeIndex(credentials *tcclient.Credentials) *index.Index {
+	return clientfactory.New(credentials).WithBaseURL("index", indexBaseURL).Index()
+}
+
+// runIndexedArtifact finds the task indexed at a namespace and downloads an
+// artifact of its latest run, to stdout or to the file given with --output.
+func runIndexedArtifact(credentials *tcclient.Credentials, args []string, out io.Writer, flagSet *pflag.FlagSet) error {
+	namespace, name := args[0], args[1]
+
+	indexed, err := makeIndex(credentials).FindTask(namespace)
+	if err != nil {
+		return fmt.Errorf("could not find the task indexed at %s: %v", namespace, err)
+	}
+	taskID := indexed.TaskID
+
+	q := makeQueue(credentials)
+	u := q.BaseURL + "/task/" + url.QueryEscape(taskID) + "/artifacts/" + url.QueryEscape(name)
+	if credentials != nil {
+		signed, err := q.GetLatestArtifact_SignedURL(taskID, name, signedURLDuration)
+		if err != nil {
+			return fmt.Errorf("could not sign the URL: %v", err)
+		}
+		u = signed.String()
+	}
+	if path, _ := flagSet.GetString("output"); path != "" && path != "-" {
+		_, err = downloadFile(u, path)
+	} else {
+		_, err = download(u, out, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("could not download %s of task %s: %v", name, taskID, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package task
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+)
+
+const fakeNamespace = "project.fake.latest"
+
+// indexes the test task at fakeNamespace
+func indexedTaskHandler(w http.ResponseWriter, _ *http.Request) {
+	io.WriteString(w, `{"namespace": "`+fakeNamespace+`", "taskId": "`+fakeTaskID+`", "rank": 0}`)
+}
+
+func (suite *FakeServerSuite) TestIndexedArtifactStdout() {
+	buf, cmd := setUpCommand()
+	cmd.Flags().String("output", "", "")
+
+	args := []string{fakeNamespace, "fake_live_backing.log"}
+	suite.NoError(runIndexedArtifact(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+	suite.Equal("hello world\n", buf.String())
+}
+
+func (suite *FakeServerSuite) TestIndexedArtifactOutput() {
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	suite.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "live.log")
+
+	buf, cmd := setUpCommand()
+	cmd.Flags().String("output", path, "")
+
+	args := []string{fakeNamespace, "fake_live_backing.log"}
+	suite.NoError(runIndexedArtifact(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+	suite.Empty(buf.String())
+	data, err := ioutil.ReadFile(path)
+	suite.NoError(err)
+	suite.Equal("hello world\n", string(data))
+}
+
+func (suite *FakeServerSuite) TestIndexedArtifactMissing() {
+	_, cmd := setUpCommand()
+	cmd.Flags().String("output", "", "")
+
+	args := []string{fakeNamespace, "nope.log"}
+	suite.Error(runIndexedArtifact(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+	args = []string{"project.unknown", "fake_live_backing.log"}
+	suite.Error(runIndexedArtifact(&tcclient.Credentials{}, args, cmd.OutOrStdout(), cmd.Flags()))
+}
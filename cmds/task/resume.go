@@ -0,0 +1,196 @@
+package task
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/taskcluster/taskcluster-cli/client"
+)
+
+var (
+	// downloadAttempts is how many times an artifact download is attempted
+	// before giving up on transient failures.
+	downloadAttempts = 5
+	// downloadBackoff is the delay before the first retry of a download; it
+	// doubles with every retry. It is a variable so tests can shorten it.
+	downloadBackoff = time.Second
+)
+
+// checksumHeader is the header S3 reports the SHA-256 of blob artifacts in,
+// as hex, when the artifact was uploaded with one.
+const checksumHeader = "x-amz-meta-content-sha256"
+
+// downloadFile saves the content at u to path, see download, and returns its
+// size. The file is removed on failure, so a truncated artifact isn't left
+// behind.
+func downloadFile(u, path string) (int64, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	size, err := download(u, file, func() error {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		return file.Truncate(0)
+	})
+	if err2 := file.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		os.Remove(path)
+		return 0, err
+	}
+	return size, nil
+}
+
+// download writes the content at u to w and returns its size. Transient
+// failures, such as network errors, 5xx responses and responses cut short,
+// are retried with backoff, up to downloadAttempts times. A retry resumes
+// where the previous attempt stopped, with a range request, if the server
+// accepts them and the content isn't encoded, e.g. gzipped; otherwise the
+// download starts over once rewind, if not nil, has reset w.
+//
+// The size is checked against the Content-Length, and the content against the
+// SHA-256 checksum the server reports, if any.
+func download(u string, w io.Writer, rewind func() error) (int64, error) {
+	d := &resumableDownload{w: &recordingWriter{w: w}, rewind: rewind, size: -1, hash: sha256.New()}
+	var err error
+	for attempt := 0; attempt < downloadAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(downloadBackoff << uint(attempt-1))
+		}
+		var transient bool
+		if transient, err = d.attempt(u); err == nil || !transient {
+			break
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	return d.written, d.verify()
+}
+
+// resumableDownload is the state of a download carried over its attempts.
+type resumableDownload struct {
+	w      *recordingWriter
+	rewind func() error
+
+	// written is how much of the content was written to w
+	written int64
+	// size is the size of the whole content, or -1 if unknown
+	size int64
+	// checksum is the SHA-256 checksum reported by the server, if any
+	checksum string
+	// acceptRanges tells whether the server accepts range requests
+	acceptRanges bool
+	hash         hash.Hash
+}
+
+// attempt makes one attempt at downloading the rest of the content at u, and
+// tells whether its error, if any, is transient.
+func (d *resumableDownload) attempt(u string) (transient bool, err error) {
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return false, err
+	}
+	resuming := d.written > 0 && d.acceptRanges
+	if resuming {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.written))
+	}
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resuming && resp.StatusCode == http.StatusPartialContent:
+		if start := contentRangeStart(resp.Header.Get("Content-Range")); start != d.written {
+			return false, fmt.Errorf("could not resume the download at %d bytes, the server sent a range starting at %d", d.written, start)
+		}
+	case resp.StatusCode/100 == 2:
+		if d.written > 0 {
+			// the content is sent from the start again
+			if d.rewind == nil {
+				return false, fmt.Errorf("the download stopped after %d bytes and the server can't resume it", d.written)
+			}
+			if err = d.rewind(); err != nil {
+				return false, err
+			}
+			d.written = 0
+			d.hash.Reset()
+		}
+		d.size = resp.ContentLength
+		d.checksum = resp.Header.Get(checksumHeader)
+		// ranges of content-encoded responses, such as gzipped logs which the
+		// transport decompresses, are offsets into the encoded content, not
+		// into what was written
+		d.acceptRanges = resp.Header.Get("Accept-Ranges") == "bytes" && !resp.Uncompressed && resp.Header.Get("Content-Encoding") == ""
+	case resp.StatusCode/100 == 5:
+		return true, fmt.Errorf("received unexpected response code %v", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("received unexpected response code %v", resp.StatusCode)
+	}
+
+	n, err := io.Copy(io.MultiWriter(d.w, d.hash), resp.Body)
+	d.written += n
+	if err != nil {
+		// failing to write the content won't get better by retrying
+		return d.w.err == nil, err
+	}
+	if d.size >= 0 && d.written < d.size {
+		return true, fmt.Errorf("the download stopped after %d of %d bytes", d.written, d.size)
+	}
+	return false, nil
+}
+
+// verify checks the content written against its size and checksum.
+func (d *resumableDownload) verify() error {
+	if d.size >= 0 && d.written != d.size {
+		return fmt.Errorf("downloaded %d bytes, expected %d", d.written, d.size)
+	}
+	if d.checksum != "" {
+		if sum := hex.EncodeToString(d.hash.Sum(nil)); !strings.EqualFold(sum, d.checksum) {
+			return fmt.Errorf("the SHA-256 of the download is %s, expected %s", sum, d.checksum)
+		}
+	}
+	return nil
+}
+
+// contentRangeStart returns the first byte of a Content-Range header value,
+// such as "bytes 100-199/200", or -1 if it can't be parsed.
+func contentRangeStart(value string) int64 {
+	if !strings.HasPrefix(value, "bytes ") {
+		return -1
+	}
+	p := strings.SplitN(strings.TrimPrefix(value, "bytes "), "-", 2)
+	start, err := strconv.ParseInt(p[0], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
+
+// recordingWriter records the error of the writes to w, to tell them from
+// the errors reading the response.
+type recordingWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (rw *recordingWriter) Write(p []byte) (int, error) {
+	n, err := rw.w.Write(p)
+	if err != nil {
+		rw.err = err
+	}
+	return n, err
+}
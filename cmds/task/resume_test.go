@@ -0,0 +1,211 @@
+package task
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+const resumeContent = "hello resumable world\n"
+
+// flakyHandler serves resumeContent, cutting the first response short after
+// 5 bytes. With ranges, it accepts range requests.
+type flakyHandler struct {
+	ranges   bool
+	checksum string
+	requests []string
+}
+
+func (h *flakyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.requests = append(h.requests, r.Header.Get("Range"))
+	if h.checksum != "" {
+		w.Header().Set(checksumHeader, h.checksum)
+	}
+	if len(h.requests) == 1 {
+		if h.ranges {
+			w.Header().Set("Accept-Ranges", "bytes")
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(resumeContent)))
+		io.WriteString(w, resumeContent[:5])
+		return
+	}
+	if h.ranges {
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(resumeContent))
+		return
+	}
+	io.WriteString(w, resumeContent)
+}
+
+func shortBackoff() func() {
+	backoff := downloadBackoff
+	downloadBackoff = time.Millisecond
+	return func() { downloadBackoff = backoff }
+}
+
+func TestDownloadResumes(t *testing.T) {
+	assert := assert.New(t)
+	defer shortBackoff()()
+
+	sum := sha256.Sum256([]byte(resumeContent))
+	h := &flakyHandler{ranges: true, checksum: hex.EncodeToString(sum[:])}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	size, err := download(server.URL, buf, nil)
+	assert.NoError(err)
+	assert.Equal(int64(len(resumeContent)), size)
+	assert.Equal(resumeContent, buf.String())
+	assert.Equal([]string{"", "bytes=5-"}, h.requests)
+}
+
+func TestDownloadRestarts(t *testing.T) {
+	assert := assert.New(t)
+	defer shortBackoff()()
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "artifact")
+
+	// without ranges, a file is written from the start again
+	h := &flakyHandler{}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	size, err := downloadFile(server.URL, path)
+	assert.NoError(err)
+	assert.Equal(int64(len(resumeContent)), size)
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal(resumeContent, string(data))
+	assert.Equal([]string{"", ""}, h.requests)
+
+	// which stdout can't be
+	h.requests = nil
+	_, err = download(server.URL, &bytes.Buffer{}, nil)
+	assert.Error(err)
+}
+
+func TestDownloadChecksumMismatch(t *testing.T) {
+	assert := assert.New(t)
+	defer shortBackoff()()
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "artifact")
+
+	server := httptest.NewServer(&flakyHandler{ranges: true, checksum: strings.Repeat("0", 64)})
+	defer server.Close()
+
+	_, err = downloadFile(server.URL, path)
+	assert.Error(err)
+	assert.Contains(err.Error(), "SHA-256")
+	_, err = os.Stat(path)
+	assert.True(os.IsNotExist(err), "a corrupt download shouldn't be left behind")
+}
+
+func TestDownloadRetries(t *testing.T) {
+	assert := assert.New(t)
+	defer shortBackoff()()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Path == "/missing":
+			http.NotFound(w, r)
+		case calls < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			io.WriteString(w, resumeContent)
+		}
+	}))
+	defer server.Close()
+
+	buf := &bytes.Buffer{}
+	_, err := download(server.URL, buf, nil)
+	assert.NoError(err)
+	assert.Equal(resumeContent, buf.String())
+	assert.Equal(3, calls)
+
+	calls = 0
+	_, err = download(server.URL+"/missing", buf, nil)
+	assert.Error(err)
+	assert.Equal(1, calls, "client errors shouldn't be retried")
+}
+
+func TestContentRangeStart(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(int64(100), contentRangeStart("bytes 100-199/200"))
+	assert.Equal(int64(0), contentRangeStart("bytes 0-0/*"))
+	assert.Equal(int64(-1), contentRangeStart("items 1-2/3"))
+	assert.Equal(int64(-1), contentRangeStart(""))
+}
+
+// gzipFlakyHandler serves content gzipped, accepting range requests over the
+// gzipped bytes, and drops the connection halfway through the first response.
+type gzipFlakyHandler struct {
+	gzipped  []byte
+	requests []string
+}
+
+func (h *gzipFlakyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.requests = append(h.requests, r.Header.Get("Range"))
+	w.Header().Set("Content-Encoding", "gzip")
+	w.Header().Set("Content-Type", "text/plain")
+	if len(h.requests) == 1 {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("Content-Length", strconv.Itoa(len(h.gzipped)))
+		w.Write(h.gzipped[:len(h.gzipped)/2])
+		return
+	}
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(h.gzipped))
+}
+
+func TestDownloadGzipRestarts(t *testing.T) {
+	assert := assert.New(t)
+	defer shortBackoff()()
+
+	content := &bytes.Buffer{}
+	for i := 0; content.Len() < 200000; i++ {
+		fmt.Fprintf(content, "log line %d\n", i)
+	}
+	gzipped := &bytes.Buffer{}
+	zw := gzip.NewWriter(gzipped)
+	zw.Write(content.Bytes())
+	assert.NoError(zw.Close())
+
+	h := &gzipFlakyHandler{gzipped: gzipped.Bytes()}
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "live.log")
+
+	size, err := downloadFile(server.URL, path)
+	assert.NoError(err)
+	assert.Equal(int64(content.Len()), size)
+	data, err := ioutil.ReadFile(path)
+	assert.NoError(err)
+	assert.True(bytes.Equal(content.Bytes(), data), "the decompressed content should be downloaded intact")
+	assert.Equal([]string{"", ""}, h.requests, "gzipped downloads can't be resumed with ranges")
+}
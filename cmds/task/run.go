@@ -4,12 +4,15 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/taskcluster/slugid-go/slugid"
+	"github.com/taskcluster/taskcluster-cli/client"
 	"github.com/taskcluster/taskcluster-cli/config"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 	"github.com/taskcluster/taskcluster-client-go/queue"
@@ -51,6 +54,8 @@ func init() {
 	fs.StringVar(&runPayload.Metadata.Source, "source", "http://taskcluster-cli/task/run", "URL pointing to the source of the task")
 	fs.StringSliceVar(&runPayload.Dependencies, "dependency", []string{}, "TaskID of a dependency (repeatable)")
 	fs.IntVar(&runPayload.Retries, "retries", 5, "Number of retries due to infrastructure issues")
+	fs.BoolP("dry-run", "d", false, "Print the request that would create the task instead of creating it")
+	fs.Bool("validate-only", false, "Print the generated taskId and the validated task definition as JSON, without contacting the queue")
 
 	for _, f := range requiredFlags {
 		runCmd.MarkFlagRequired(f)
@@ -121,7 +126,21 @@ func runRunTask(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("could not marshal execution payload: %v", err)
 	}
 
-	q := queue.New(creds)
+	if err := validateTask(runPayload); err != nil {
+		return err
+	}
+	if validateOnly, _ := cmd.Flags().GetBool("validate-only"); validateOnly {
+		return printValidatedTask(cmd.OutOrStdout(), taskID, runPayload)
+	}
+
+	if endpoint := client.Endpoint(cmd.Flags(), "queue"); endpoint != "" {
+		queueBaseURL = endpoint
+	}
+	q := makeQueue(creds)
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return client.PrintRequest(cmd.OutOrStdout(), "PUT", q.BaseURL+"/task/"+url.QueryEscape(taskID), runPayload)
+	}
+
 	resp, err := q.CreateTask(taskID, runPayload)
 	if err != nil {
 		return fmt.Errorf("could not create task: %v", err)
@@ -132,3 +151,17 @@ func runRunTask(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// printValidatedTask writes the task definition task, as it would be created
+// under taskID, to out as JSON, for --validate-only.
+func printValidatedTask(out io.Writer, taskID string, task *queue.TaskDefinitionRequest) error {
+	data, err := client.MarshalJSON(struct {
+		TaskID string                       `json:"taskId"`
+		Task   *queue.TaskDefinitionRequest `json:"task"`
+	}{taskID, task})
+	if err != nil {
+		return fmt.Errorf("could not marshal the task definition: %v", err)
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
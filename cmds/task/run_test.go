@@ -1,6 +1,8 @@
 package task
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -15,3 +17,40 @@ func TestInvalidTaskCreate(t *testing.T) {
 	assert.Error(runRunTask(cmd, []string{}), "create task should error with insufficient args")
 	assert.Error(runRunTask(cmd, []string{"ubuntu:14.04"}), "create task should error with insufficient args")
 }
+
+func TestRunTaskValidateOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(provisionerID, workerType string) {
+		runPayload.ProvisionerID, runPayload.WorkerType = provisionerID, workerType
+	}(runPayload.ProvisionerID, runPayload.WorkerType)
+	runPayload.ProvisionerID, runPayload.WorkerType = "aws-provisioner-v1", "tutorial"
+
+	buf := &bytes.Buffer{}
+	cmd := &cobra.Command{}
+	cmd.SetOutput(buf)
+	cmd.Flags().StringSliceP("env", "e", []string{"GREETING=hello"}, "")
+	cmd.Flags().Bool("validate-only", true, "")
+
+	assert.NoError(runRunTask(cmd, []string{"ubuntu:14.04", "echo", "$GREETING"}))
+
+	var result struct {
+		TaskID string `json:"taskId"`
+		Task   struct {
+			TaskGroupID string `json:"taskGroupId"`
+			WorkerType  string `json:"workerType"`
+			Payload     struct {
+				Image   string            `json:"image"`
+				Command []string          `json:"command"`
+				Env     map[string]string `json:"env"`
+			} `json:"payload"`
+		} `json:"task"`
+	}
+	assert.NoError(json.Unmarshal(buf.Bytes(), &result))
+	assert.Len(result.TaskID, 22)
+	assert.Equal(result.TaskID, result.Task.TaskGroupID)
+	assert.Equal("tutorial", result.Task.WorkerType)
+	assert.Equal("ubuntu:14.04", result.Task.Payload.Image)
+	assert.Equal([]string{"echo", "$GREETING"}, result.Task.Payload.Command)
+	assert.Equal(map[string]string{"GREETING": "hello"}, result.Task.Payload.Env)
+}
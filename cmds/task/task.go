@@ -1,7 +1,12 @@
 package task
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/taskcluster/taskcluster-cli/client"
 	"github.com/taskcluster/taskcluster-cli/cmds/root"
+	"github.com/taskcluster/taskcluster-cli/timeparse"
 
 	"github.com/spf13/cobra"
 )
@@ -15,7 +20,23 @@ var (
 	statusCmd = &cobra.Command{
 		Use:   "status <taskId>",
 		Short: "Get the status of a task.",
-		RunE:  executeHelperE(runStatus),
+		Long: `Get the status of a task.
+
+With --follow, the status is polled until the task is resolved, and the exit
+code tells how it resolved, so that CI can wait on a task and gate a step:
+
+  completed    0
+  failed       1
+  exception    2
+
+or why following it stopped before that:
+
+  error        3    the status of the task could not be polled
+  interrupted  130  following was interrupted with Ctrl-C
+
+Use --exit-on-state to change them, e.g. --exit-on-state exception=0 to
+only fail on failed tasks, or --exit-on-state interrupted=1.`,
+		RunE: executeHelperE(runStatus),
 	}
 	artifactsCmd = &cobra.Command{
 		Use:   "artifacts <taskId>",
@@ -25,10 +46,44 @@ var (
 )
 
 func init() {
-	statusCmd.Flags().BoolP("all-runs", "a", false, "Check all runs of the task.")
+	client.AddEndpointFlag(Command.PersistentFlags(), "queue")
+
+	statusCmd.Flags().BoolP("all-runs", "a", false, "List all runs of the task, with their reasons, timestamps and worker.")
 	statusCmd.Flags().IntP("run", "r", -1, "Specifies which run to consider.")
+	statusCmd.Flags().BoolP("follow", "f", false, "Poll the status until the task is resolved, printing each change; exits with 0 if it completed, 1 if it failed, 2 on exception, 3 if the status could not be polled and 130 if interrupted.")
+	timeparse.AddDurationFlag(statusCmd.Flags(), "interval", 10*time.Second, "How often to poll the status with --follow.")
+	statusCmd.Flags().StringSlice("exit-on-state", nil, "Change the exit code of --follow for a state the task resolves to, or for error and interrupted (repeatable) (format: 'state=code', with state one of completed, failed, exception, error, interrupted)")
 
 	artifactsCmd.Flags().IntP("run", "r", -1, "Specifies which run to consider.")
+	artifactsCmd.Flags().Int("limit", 0, "Stop after listing this many artifacts; 0 lists them all.")
+	client.AddCountFlag(artifactsCmd.Flags())
+
+	downloadAllCmd := &cobra.Command{
+		Use:   "download-all <taskId> [runId]",
+		Short: "Download all the artifacts of a task.",
+		Long: `Downloads all the artifacts of a run of a task, the latest one by default, into
+a directory, at paths mirroring their names. Reference and error artifacts are
+skipped, and so are the artifacts whose name would be saved outside of the
+directory.`,
+		RunE: executeHelperE(runDownloadAll),
+	}
+	downloadAllCmd.Flags().StringP("output-dir", "o", "", "Directory to download the artifacts to; defaults to the taskId.")
+	artifactsCmd.AddCommand(downloadAllCmd)
+
+	indexedArtifactCmd := &cobra.Command{
+		Use:   "indexed-artifact <namespace> <artifactName>",
+		Short: "Download an artifact of the task indexed at a namespace.",
+		Long: `Finds the task indexed at a namespace, such as the latest build of a project,
+and downloads an artifact of its latest run, to stdout or to a file.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// executeHelperE only expects a taskId
+			if len(args) < 2 {
+				return fmt.Errorf("%s expects arguments <namespace> <artifactName>", cmd.Name())
+			}
+			return executeHelperE(runIndexedArtifact)(cmd, args)
+		},
+	}
+	indexedArtifactCmd.Flags().StringP("output", "o", "", "File to download the artifact to; defaults to stdout, as does '-'.")
 
 	// Commands that fetch information
 	Command.AddCommand(
@@ -48,6 +103,8 @@ func init() {
 		},
 		// artifacts
 		artifactsCmd,
+		// indexed-artifact
+		indexedArtifactCmd,
 		// log
 		&cobra.Command{
 			Use:   "log <taskId>",
@@ -57,25 +114,31 @@ func init() {
 	)
 
 	// Commands that take actions
+	cancelCmd := &cobra.Command{
+		Use:   "cancel <taskId>",
+		Short: "Cancel a task.",
+		RunE:  executeHelperE(runCancel),
+	}
+	rerunCmd := &cobra.Command{
+		Use:   "rerun <taskId>",
+		Short: "Rerun a task.",
+		RunE:  executeHelperE(runRerun),
+	}
+	completeCmd := &cobra.Command{
+		Use:   "complete <taskId>",
+		Short: "Complete the execution of a task.",
+		RunE:  executeHelperE(runComplete),
+	}
+	for _, c := range []*cobra.Command{cancelCmd, rerunCmd, completeCmd} {
+		c.Flags().BoolP("dry-run", "d", false, "Print the request(s) that would be made instead of making them.")
+	}
 	Command.AddCommand(
 		// cancel
-		&cobra.Command{
-			Use:   "cancel <taskId>",
-			Short: "Cancel a task.",
-			RunE:  executeHelperE(runCancel),
-		},
-		// cancel
-		&cobra.Command{
-			Use:   "rerun <taskId>",
-			Short: "Rerun a task.",
-			RunE:  executeHelperE(runRerun),
-		},
-		// cancel
-		&cobra.Command{
-			Use:   "complete <taskId>",
-			Short: "Complete the execution of a task.",
-			RunE:  executeHelperE(runComplete),
-		},
+		cancelCmd,
+		// rerun
+		rerunCmd,
+		// complete
+		completeCmd,
 	)
 
 	// Add the task subtree to the root.
@@ -3,9 +3,12 @@ package task
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
 	"github.com/taskcluster/taskcluster-cli/config"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 )
@@ -32,8 +35,32 @@ func executeHelperE(f Executor) func(*cobra.Command, []string) error {
 		if len(args) < 1 {
 			return fmt.Errorf("%s expects argument <taskId>", cmd.Name())
 		}
-		return f(creds, args, cmd.OutOrStdout(), cmd.Flags())
+		if endpoint := client.Endpoint(cmd.Flags(), "queue"); endpoint != "" {
+			queueBaseURL = endpoint
+		}
+		err := f(creds, args, cmd.OutOrStdout(), cmd.Flags())
+		if _, ok := err.(*root.ExitError); ok {
+			// the exit code carries the meaning, not a usage mistake
+			cmd.SilenceUsage = true
+		}
+		return err
+	}
+}
+
+// formatTime formats t as RFC3339 in UTC, or as a dash if it is unset.
+func formatTime(t tcclient.Time) string {
+	if time.Time(t).IsZero() {
+		return "-"
 	}
+	return time.Time(t).UTC().Format(time.RFC3339)
+}
+
+// orDash returns s, or a dash if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
 
 func stringFlagHelper(flagset *pflag.FlagSet, flag string) string {
@@ -43,3 +70,12 @@ func stringFlagHelper(flagset *pflag.FlagSet, flag string) string {
 	}
 	return val
 }
+
+// limitString formats a per-page limit as expected by the `limit` query-string
+// parameter of listing endpoints, where 0 means no limit.
+func limitString(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprint(limit)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	assert "github.com/stretchr/testify/require"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
 	tcclient "github.com/taskcluster/taskcluster-client-go"
 )
 
@@ -46,3 +47,31 @@ func TestStringFlagHelper(t *testing.T) {
 		stringFlagHelper(fs, "not-exists")
 	}, "should panic")
 }
+
+func TestParseExitOnState(t *testing.T) {
+	assert := assert.New(t)
+
+	m, err := parseExitOnState(nil)
+	assert.NoError(err)
+	assert.Equal(defaultExitOnState, m)
+
+	m, err = parseExitOnState([]string{"exception=0", " completed = 3 ", "interrupted=1"})
+	assert.NoError(err)
+	assert.Equal(map[string]int{"completed": 3, "failed": exitFailed, "exception": 0, "error": exitError, "interrupted": 1}, m)
+	assert.Equal(exitException, defaultExitOnState["exception"], "the defaults should not change")
+
+	for _, v := range []string{"exception", "running=1", "failed=x", "failed=256"} {
+		_, err = parseExitOnState([]string{v})
+		assert.Error(err, v)
+	}
+}
+
+func TestResolvedError(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(resolvedError("abc", "completed", defaultExitOnState))
+	err := resolvedError("abc", "completed", map[string]int{"completed": 4})
+	assert.Equal(4, root.ExitCode(err))
+	assert.Contains(err.Error(), "completed")
+	assert.Equal(exitException, root.ExitCode(resolvedError("abc", "exception", defaultExitOnState)))
+}
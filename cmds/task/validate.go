@@ -0,0 +1,58 @@
+package task
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/taskcluster/taskcluster-cli/apis"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// createTaskSchema is the id of the schema of the createTask request payload.
+const createTaskSchema = "http://schemas.taskcluster.net/queue/v1/create-task-request.json#"
+
+// validateTask checks task against the schema of the createTask request
+// payload, so that mistakes are caught before making the request. The error
+// lists each failure along with the JSON pointer of the field at fault.
+func validateTask(task interface{}) error {
+	schema, ok := apis.Schema(createTaskSchema)
+	if !ok {
+		return fmt.Errorf("could not find the schema %s", createTaskSchema)
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("could not marshal the task definition: %v", err)
+	}
+
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewStringLoader(schema),
+		gojsonschema.NewBytesLoader(data),
+	)
+	if err != nil {
+		return fmt.Errorf("could not validate the task definition: %v", err)
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	failures := make([]string, 0, len(result.Errors()))
+	for _, e := range result.Errors() {
+		failures = append(failures, fmt.Sprintf("  %s: %s", jsonPointer(e), e.Description()))
+	}
+	return fmt.Errorf("invalid task definition:\n%s", strings.Join(failures, "\n"))
+}
+
+// jsonPointer returns the JSON pointer (RFC 6901) of the field a validation
+// error is about; for a missing property, that is the property itself rather
+// than the object lacking it.
+func jsonPointer(e gojsonschema.ResultError) string {
+	path := strings.TrimPrefix(e.Context().String("/"), gojsonschema.STRING_CONTEXT_ROOT)
+	if property, ok := e.Details()["property"].(string); ok && e.Type() == "required" {
+		path += "/" + property
+	}
+	if path == "" {
+		return "/"
+	}
+	return path
+}
@@ -0,0 +1,60 @@
+package task
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	assert "github.com/stretchr/testify/require"
+	tcclient "github.com/taskcluster/taskcluster-client-go"
+	"github.com/taskcluster/taskcluster-client-go/queue"
+)
+
+func validTask() *queue.TaskDefinitionRequest {
+	now := time.Now().UTC()
+	task := &queue.TaskDefinitionRequest{
+		ProvisionerID: "aws-provisioner-v1",
+		WorkerType:    "tutorial",
+		SchedulerID:   "taskcluster-cli",
+		Created:       tcclient.Time(now),
+		Deadline:      tcclient.Time(now.Add(24 * time.Hour)),
+		Expires:       tcclient.Time(now.Add(24*time.Hour).AddDate(1, 0, 0)),
+		Payload:       json.RawMessage(`{"image": "ubuntu", "command": ["true"]}`),
+	}
+	task.Metadata.Name = "test"
+	task.Metadata.Description = "test"
+	task.Metadata.Owner = "name@example.com"
+	task.Metadata.Source = "http://taskcluster-cli/task/run"
+	return task
+}
+
+func TestValidateTask(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(validateTask(validTask()))
+}
+
+func TestValidateTaskInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	task := validTask()
+	task.ProvisionerID = ""
+	task.Metadata.Owner = "nobody"
+
+	err := validateTask(task)
+	assert.Error(err)
+	assert.Contains(err.Error(), "  /provisionerId: ")
+	assert.Contains(err.Error(), "  /metadata/owner: ")
+}
+
+func TestValidateTaskMissingField(t *testing.T) {
+	assert := assert.New(t)
+
+	// a raw definition, as the typed one can't go without its fields
+	err := validateTask(map[string]interface{}{
+		"provisionerId": "aws-provisioner-v1",
+	})
+	assert.Error(err)
+	assert.Contains(err.Error(), "  /workerType: ")
+	assert.Contains(err.Error(), "  /metadata: ")
+}
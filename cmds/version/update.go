@@ -0,0 +1,257 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/shibukawa/configdir"
+	"github.com/spf13/cobra"
+	"github.com/taskcluster/taskcluster-cli/client"
+	"github.com/taskcluster/taskcluster-cli/cmds/root"
+)
+
+const (
+	// updateCheckInterval is how often the latest release is looked up
+	updateCheckInterval = 24 * time.Hour
+	// updateCheckTimeout bounds the lookup of the latest release, which
+	// runs in the background
+	updateCheckTimeout = 10 * time.Second
+	// updateCheckWait is how long a command which is done waits for the
+	// lookup of the latest release to finish before exiting
+	updateCheckWait = time.Second
+	// releasesPage is where users are pointed to upgrade
+	releasesPage = "https://github.com/taskcluster/taskcluster-cli/releases"
+)
+
+var (
+	// latestReleaseURL is the GitHub API endpoint describing the latest
+	// release; it is a variable so tests can replace it
+	latestReleaseURL = "https://api.github.com/repos/taskcluster/taskcluster-cli/releases/latest"
+	// latestReleaseCachePath is where the latest release is cached, in the
+	// cache folder
+	latestReleaseCachePath = filepath.Join("cmds", "version", "latestRelease.json")
+	// notices is where the upgrade hint is printed
+	notices io.Writer = os.Stderr
+	// notice is the upgrade hint to print once the command is done, if any
+	notice string
+	// updateChecked is closed once the lookup of the latest release started
+	// by checkForUpdate, if any, is done
+	updateChecked chan struct{}
+
+	// updateCache returns the cache folder; it is a variable so tests can
+	// replace it
+	updateCache = func() *configdir.Config {
+		return configdir.New("taskcluster", "taskcluster-cli").QueryCacheFolder()
+	}
+	// stderrIsTerminal tells whether stderr is a terminal; it is a variable so
+	// tests can replace it
+	stderrIsTerminal = func() bool { return isatty.IsTerminal(os.Stderr.Fd()) }
+)
+
+// latestRelease is the content of the cache of the latest release.
+type latestRelease struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Version   string    `json:"version"`
+}
+
+func init() {
+	root.Command.PersistentFlags().Bool("no-update-check", false, "Don't check for a newer version of taskcluster; same as setting TASKCLUSTER_NO_UPDATE_CHECK.")
+	root.PreRunHooks = append(root.PreRunHooks, checkForUpdate)
+	root.PostRunHooks = append(root.PostRunHooks, waitForUpdateCheck, printUpdateNotice)
+}
+
+// updateCheckDisabled tells whether the update check should be skipped for
+// cmd: when asked to, for development builds, and when the output is meant
+// for programs rather than humans.
+func updateCheckDisabled(cmd *cobra.Command) bool {
+	if disabled, _ := cmd.Flags().GetBool("no-update-check"); disabled || os.Getenv("TASKCLUSTER_NO_UPDATE_CHECK") != "" {
+		return true
+	}
+	if _, ok := parseVersion(VersionNumber); !ok {
+		return true
+	}
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return true
+	}
+	if format, _ := cmd.Flags().GetString("format"); format == "json" {
+		return true
+	}
+	return !stderrIsTerminal()
+}
+
+// checkForUpdate prepares the upgrade hint from the cached latest release,
+// and refreshes the cache in the background when it is stale, so that the
+// command barely waits on GitHub; the refreshed cache serves the next run.
+//
+// The time of the attempt is cached before the lookup, so that a lookup which
+// fails, or doesn't finish before the command exits, isn't attempted again
+// before updateCheckInterval either.
+func checkForUpdate(cmd *cobra.Command) {
+	if updateCheckDisabled(cmd) {
+		return
+	}
+	cache := updateCache()
+	cached, err := readLatestRelease(cache)
+	if err == nil {
+		notice = updateNotice(VersionNumber, cached.Version)
+	}
+	if err == nil && time.Since(cached.CheckedAt) <= updateCheckInterval {
+		return
+	}
+
+	attempt := &latestRelease{CheckedAt: time.Now().UTC()}
+	if err == nil {
+		attempt.Version = cached.Version
+	}
+	if writeLatestRelease(cache, attempt) != nil {
+		// the lookup couldn't be cached either
+		return
+	}
+	done := make(chan struct{})
+	updateChecked = done
+	go func() {
+		defer close(done)
+		refreshLatestRelease(cache)
+	}()
+}
+
+// waitForUpdateCheck gives the lookup of the latest release, if any, up to
+// updateCheckWait to finish, so that its result is cached.
+func waitForUpdateCheck(_ *cobra.Command) {
+	if updateChecked == nil {
+		return
+	}
+	select {
+	case <-updateChecked:
+	case <-time.After(updateCheckWait):
+	}
+}
+
+// printUpdateNotice prints the upgrade hint, if there is one.
+func printUpdateNotice(_ *cobra.Command) {
+	if notice != "" {
+		fmt.Fprintln(notices, notice)
+	}
+}
+
+// updateNotice returns the upgrade hint for the current version, or an empty
+// string if latest isn't newer.
+func updateNotice(current, latest string) string {
+	c, ok1 := parseVersion(current)
+	l, ok2 := parseVersion(latest)
+	if !ok1 || !ok2 || !newer(l, c) {
+		return ""
+	}
+	return fmt.Sprintf("taskcluster %s is available, you have %s; see %s", latest, current, releasesPage)
+}
+
+// parseVersion returns the major, minor and patch numbers of a version such as
+// v1.2.3, or v1.2.3-4-gabcdef as given by git describe.
+func parseVersion(version string) ([3]int, bool) {
+	var v [3]int
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return v, false
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// newer tells whether version a is newer than version b.
+func newer(a, b [3]int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+	return false
+}
+
+func readLatestRelease(cache *configdir.Config) (*latestRelease, error) {
+	data, err := cache.ReadFile(latestReleaseCachePath)
+	if err != nil {
+		return nil, err
+	}
+	var release latestRelease
+	if err = json.Unmarshal(data, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// refreshLatestRelease looks up the latest release and caches it. Failures
+// leave the cache as it is.
+func refreshLatestRelease(cache *configdir.Config) error {
+	version, err := fetchLatestRelease(latestReleaseURL)
+	if err != nil {
+		return err
+	}
+	return writeLatestRelease(cache, &latestRelease{CheckedAt: time.Now().UTC(), Version: version})
+}
+
+// writeLatestRelease caches release.
+func writeLatestRelease(cache *configdir.Config, release *latestRelease) error {
+	data, err := json.Marshal(release)
+	if err != nil {
+		return err
+	}
+	// write then rename, so that exiting mid-write leaves no corrupt cache
+	path := filepath.Join(cache.Path, latestReleaseCachePath)
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(path), ".latestRelease")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	if err2 := file.Close(); err == nil {
+		err = err2
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// fetchLatestRelease returns the tag of the latest release described at u.
+func fetchLatestRelease(u string) (string, error) {
+	httpClient := *client.HTTPClient
+	httpClient.Timeout = updateCheckTimeout
+	resp, err := httpClient.Get(u)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("unexpected status code %v from %v", resp.StatusCode, u)
+	}
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", err
+	}
+	return release.TagName, nil
+}
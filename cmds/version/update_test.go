@@ -0,0 +1,170 @@
+package version
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/configdir"
+	"github.com/spf13/cobra"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	for version, expected := range map[string][3]int{
+		"v1.2.3":            {1, 2, 3},
+		"1.2.3":             {1, 2, 3},
+		"v1.10.0-4-gabcdef": {1, 10, 0},
+		"v2.0.0+dirty":      {2, 0, 0},
+	} {
+		v, ok := parseVersion(version)
+		assert.True(ok, version)
+		assert.Equal(expected, v, version)
+	}
+	for _, version := range []string{"", "abcdef", "v1.2", "v1.2.x", "v1.-2.3"} {
+		_, ok := parseVersion(version)
+		assert.False(ok, version)
+	}
+}
+
+func TestUpdateNotice(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Contains(updateNotice("v1.2.3", "v1.10.0"), "v1.10.0 is available, you have v1.2.3")
+	assert.Contains(updateNotice("v1.2.3-4-gabcdef", "v1.2.4"), "v1.2.4 is available")
+	assert.Equal("", updateNotice("v1.2.3", "v1.2.3"))
+	assert.Equal("", updateNotice("v1.3.0", "v1.2.9"))
+	assert.Equal("", updateNotice("v1.2.3", "not-a-version"))
+}
+
+func TestUpdateCheckDisabled(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(v string) { VersionNumber = v }(VersionNumber)
+	VersionNumber = "v1.0.0"
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("no-update-check", false, "")
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().String("format", "", "")
+
+	cmd.Flags().Set("json", "true")
+	assert.True(updateCheckDisabled(cmd), "--json should disable the check")
+	cmd.Flags().Set("json", "false")
+
+	cmd.Flags().Set("format", "json")
+	assert.True(updateCheckDisabled(cmd), "--format json should disable the check")
+	cmd.Flags().Set("format", "")
+
+	cmd.Flags().Set("no-update-check", "true")
+	assert.True(updateCheckDisabled(cmd), "--no-update-check should disable the check")
+	cmd.Flags().Set("no-update-check", "false")
+
+	os.Setenv("TASKCLUSTER_NO_UPDATE_CHECK", "1")
+	assert.True(updateCheckDisabled(cmd), "TASKCLUSTER_NO_UPDATE_CHECK should disable the check")
+	os.Unsetenv("TASKCLUSTER_NO_UPDATE_CHECK")
+
+	VersionNumber = "abcdef"
+	assert.True(updateCheckDisabled(cmd), "development builds should not be checked")
+}
+
+func TestRefreshLatestRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"tag_name": "v9.8.7", "name": "v9.8.7"}`)
+	}))
+	defer server.Close()
+	defer func(u string) { latestReleaseURL = u }(latestReleaseURL)
+	latestReleaseURL = server.URL
+
+	dir, err := ioutil.TempDir("", "update-check")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := &configdir.Config{Path: dir, Type: configdir.Cache}
+
+	_, err = readLatestRelease(cache)
+	assert.Error(err, "nothing should be cached yet")
+
+	assert.NoError(refreshLatestRelease(cache))
+	release, err := readLatestRelease(cache)
+	assert.NoError(err)
+	assert.Equal("v9.8.7", release.Version)
+	assert.False(release.CheckedAt.IsZero())
+}
+
+func TestRefreshLatestReleaseFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	defer func(u string) { latestReleaseURL = u }(latestReleaseURL)
+	latestReleaseURL = server.URL
+
+	dir, err := ioutil.TempDir("", "update-check")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := &configdir.Config{Path: dir, Type: configdir.Cache}
+
+	assert.Error(refreshLatestRelease(cache))
+	assert.False(cache.Exists(latestReleaseCachePath), "failures should not be cached")
+}
+
+func TestCheckForUpdateStaleCacheOffline(t *testing.T) {
+	assert := assert.New(t)
+
+	defer func(v string) { VersionNumber = v }(VersionNumber)
+	VersionNumber = "v1.0.0"
+	defer func(f func() bool) { stderrIsTerminal = f }(stderrIsTerminal)
+	stderrIsTerminal = func() bool { return true }
+	defer func() { notice, updateChecked = "", nil }()
+
+	dir, err := ioutil.TempDir("", "update-check")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	cache := &configdir.Config{Path: dir, Type: configdir.Cache}
+	defer func(f func() *configdir.Config) { updateCache = f }(updateCache)
+	updateCache = func() *configdir.Config { return cache }
+
+	stale := time.Now().Add(-2 * updateCheckInterval).UTC()
+	assert.NoError(writeLatestRelease(cache, &latestRelease{CheckedAt: stale, Version: "v1.2.0"}))
+
+	// no network: the server is gone
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer func(u string) { latestReleaseURL = u }(latestReleaseURL)
+	latestReleaseURL = server.URL
+	server.Close()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("no-update-check", false, "")
+	checkForUpdate(cmd)
+	assert.NotNil(updateChecked, "a stale cache should be refreshed")
+	waitForUpdateCheck(cmd)
+	assert.Contains(notice, "v1.2.0 is available", "the cached release should still be used")
+
+	release, err := readLatestRelease(cache)
+	assert.NoError(err)
+	assert.Equal("v1.2.0", release.Version)
+	assert.True(release.CheckedAt.After(stale), "the failed attempt should be recorded")
+
+	// the next run backs off, even though the lookup failed
+	requests := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"tag_name": "v9.8.7"}`)
+	}))
+	defer server.Close()
+	latestReleaseURL = server.URL
+	updateChecked = nil
+	checkForUpdate(cmd)
+	assert.Nil(updateChecked)
+	assert.Equal(0, requests)
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/taskcluster/taskcluster-cli/client"
+)
+
+// CredentialsFileEnvVar is the environment variable naming a JSON file to load
+// the credentials from, like the --credentials-file flag.
+const CredentialsFileEnvVar = "TASKCLUSTER_CREDENTIALS_FILE"
+
+// LoadCredentialsFile reads the credentials in the JSON file at path, such as
+// a mounted secret, of the form {"clientId": ..., "accessToken": ...,
+// "certificate": ...}, the certificate being optional.
+func LoadCredentialsFile(path string) (*client.Credentials, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read credentials file %s: %v", path, err)
+	}
+	var creds client.Credentials
+	if err = json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("could not parse credentials file %s: %v", path, err)
+	}
+	if creds.ClientID == "" || creds.AccessToken == "" {
+		return nil, fmt.Errorf("credentials file %s must have a clientId and an accessToken", path)
+	}
+	return &creds, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestLoadCredentialsFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "credentials.json")
+	assert.NoError(ioutil.WriteFile(path, []byte(`{"clientId": "tester", "accessToken": "no-secret", "certificate": "{}"}`), 0600))
+	creds, err := LoadCredentialsFile(path)
+	assert.NoError(err)
+	assert.Equal("tester", creds.ClientID)
+	assert.Equal("no-secret", creds.AccessToken)
+	assert.Equal("{}", creds.Certificate)
+
+	assert.NoError(ioutil.WriteFile(path, []byte(`{"clientId": "tester"}`), 0600))
+	_, err = LoadCredentialsFile(path)
+	assert.Error(err, "an accessToken is required")
+
+	_, err = LoadCredentialsFile(filepath.Join(dir, "missing.json"))
+	assert.Error(err)
+}
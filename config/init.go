@@ -11,11 +11,20 @@ var (
 	// Configuration contains the current configuration values.
 	Configuration map[string]map[string]interface{}
 
+	// Sources tells where each of the current configuration values was
+	// loaded from.
+	Sources map[string]map[string]Source
+
 	// OptionsDefinitions is a map of all the OptionDefinitions, by command.
 	OptionsDefinitions = make(map[string]map[string]OptionDefinition)
 
 	// Credentials is the client credentials, if present.
 	Credentials *client.Credentials
+
+	// CredentialsSource tells where Credentials were loaded from, as shown by
+	// config show: the source of the clientId option, or the credentials
+	// file along with what named it.
+	CredentialsSource string
 )
 
 // Setup is to be called from main
@@ -26,16 +35,20 @@ func Setup() {
 	var err error
 
 	// load configuration
-	Configuration, err = Load()
+	Configuration, Sources, err = LoadWithSources()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to load configuration file, error: %s\n", err)
 		os.Exit(1)
 	}
 
-	// load credentials
-	clientID, ok1 := Configuration["config"]["clientId"].(string)
-	accessToken, ok2 := Configuration["config"]["accessToken"].(string)
-	if ok1 && ok2 {
+	// load credentials: from the environment, then the file named by
+	// TASKCLUSTER_CREDENTIALS_FILE, then the configuration file; the
+	// --credentials-file flag overrides them all, once flags are parsed;
+	// the options default to empty strings, which mean no credentials
+	Credentials, CredentialsSource = nil, ""
+	clientID, _ := Configuration["config"]["clientId"].(string)
+	accessToken, _ := Configuration["config"]["accessToken"].(string)
+	if clientID != "" && accessToken != "" {
 		certificate, _ := Configuration["config"]["certificate"].(string)
 		authorizedScopes, _ := Configuration["config"]["authorizedScopes"].([]string)
 		Credentials = &client.Credentials{
@@ -44,5 +57,16 @@ func Setup() {
 			Certificate:      certificate,
 			AuthorizedScopes: authorizedScopes,
 		}
+		CredentialsSource = string(Sources["config"]["clientId"])
+		if Sources["config"]["clientId"] == SourceEnv {
+			CredentialsSource += " " + OptionsDefinitions["config"]["clientId"].Env
+		}
+	}
+	if path := os.Getenv(CredentialsFileEnvVar); path != "" && Sources["config"]["clientId"] != SourceEnv {
+		if Credentials, err = LoadCredentialsFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load credentials from %s, error: %s\n", CredentialsFileEnvVar, err)
+			os.Exit(1)
+		}
+		CredentialsSource = CredentialsFileEnvVar + " " + path
 	}
 }
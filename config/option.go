@@ -21,3 +21,13 @@ func RegisterOptions(command string, options map[string]OptionDefinition) {
 		OptionsDefinitions[command][key] = option
 	}
 }
+
+// Source tells where the value of a configuration option was loaded from.
+type Source string
+
+// The sources of configuration values, see LoadWithSources.
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+)
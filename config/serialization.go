@@ -27,13 +27,27 @@ func configFile() string {
 	return filepath.Join(configFolder, "taskcluster.yml")
 }
 
+// FilePath returns the location of the configuration file, whether or not it
+// exists.
+func FilePath() string {
+	return configFile()
+}
+
 // Load will load confiration file, and initialize a default configuration
 // if no configuration is present. This only returns an error if a configuration
 // file is present, but we are unable to parse it.
-// TODO	we could simplify this function and only go through the definitions once
-//		and what happens if a value is tied to an env var AND to the config file?
 func Load() (map[string]map[string]interface{}, error) {
+	config, _, err := LoadWithSources()
+	return config, err
+}
+
+// LoadWithSources is like Load, but also returns where each value was loaded
+// from. Environment variables take precedence over the configuration file,
+// which takes precedence over the defaults.
+// TODO	we could simplify this function and only go through the definitions once
+func LoadWithSources() (map[string]map[string]interface{}, map[string]map[string]Source, error) {
 	config := make(map[string]map[string]interface{})
+	sources := make(map[string]map[string]Source)
 
 	// Read config file and unmarshal into config overwriting default values
 	// if ioutil.ReadFile returns an error, it means the config file couldn't
@@ -41,22 +55,32 @@ func Load() (map[string]map[string]interface{}, error) {
 	configFile := configFile()
 	if data, err := ioutil.ReadFile(configFile); err == nil {
 		if err = yaml.Unmarshal(data, &config); err != nil {
-			return nil, fmt.Errorf(
+			return nil, nil, fmt.Errorf(
 				"read config file %s, but failed to parse YAML, error: %s",
 				configFile, err,
 			)
 		}
 	}
+	for command, options := range config {
+		sources[command] = make(map[string]Source)
+		for option := range options {
+			sources[command][option] = SourceFile
+		}
+	}
 
 	// Populate missing config fields with default values
 	for command, options := range OptionsDefinitions {
 		if _, ok := config[command]; !ok {
 			config[command] = make(map[string]interface{})
 		}
+		if _, ok := sources[command]; !ok {
+			sources[command] = make(map[string]Source)
+		}
 
 		for option, definition := range options {
 			if _, ok := config[command][option]; !ok {
 				config[command][option] = definition.Default
+				sources[command][option] = SourceDefault
 			}
 		}
 	}
@@ -79,7 +103,7 @@ func Load() (map[string]map[string]interface{}, error) {
 			var value interface{}
 			if definition.Parse {
 				if err := json.Unmarshal([]byte(val), &value); err != nil {
-					return nil, fmt.Errorf(
+					return nil, nil, fmt.Errorf(
 						"failed to parse environment variable '%s' for config option '%s.%s', error: %s",
 						definition.Env, command, option, err,
 					)
@@ -91,7 +115,7 @@ func Load() (map[string]map[string]interface{}, error) {
 			// validate value (so we can show an error messages showing where we got it)
 			if definition.Validate != nil {
 				if err := definition.Validate(value); err != nil {
-					return nil, fmt.Errorf(
+					return nil, nil, fmt.Errorf(
 						"invalid value for config option '%s.%s' loaded from environment variable '%s', error: %s",
 						command, option, definition.Env, err,
 					)
@@ -100,6 +124,7 @@ func Load() (map[string]map[string]interface{}, error) {
 
 			// store the value
 			config[command][option] = value
+			sources[command][option] = SourceEnv
 		}
 	}
 
@@ -117,7 +142,7 @@ func Load() (map[string]map[string]interface{}, error) {
 			// otherwise validate
 			if err := definition.Validate(value); err != nil {
 				val, _ := json.Marshal(value)
-				return nil, fmt.Errorf(
+				return nil, nil, fmt.Errorf(
 					"invalid value '%s' for config option '%s.%s', error: %s",
 					val, command, option, err,
 				)
@@ -125,7 +150,7 @@ func Load() (map[string]map[string]interface{}, error) {
 		}
 	}
 
-	return config, nil
+	return config, sources, nil
 }
 
 // Save will save configuration.
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestLoadWithSources(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	os.Setenv("XDG_CONFIG_HOME", dir)
+
+	RegisterOptions("testing", map[string]OptionDefinition{
+		"fromDefault": {Default: "default"},
+		"fromFile":    {Default: "default", Env: "TASKCLUSTER_TESTING_FROM_FILE"},
+		"fromEnv":     {Default: "default", Env: "TASKCLUSTER_TESTING_FROM_ENV"},
+		"parsed":      {Default: 0.0, Env: "TASKCLUSTER_TESTING_PARSED", Parse: true},
+	})
+	defer delete(OptionsDefinitions, "testing")
+
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "taskcluster.yml"), []byte("testing:\n  fromFile: file\n  fromEnv: file\n"), 0600))
+	defer os.Unsetenv("TASKCLUSTER_TESTING_FROM_ENV")
+	os.Setenv("TASKCLUSTER_TESTING_FROM_ENV", "env")
+	defer os.Unsetenv("TASKCLUSTER_TESTING_PARSED")
+	os.Setenv("TASKCLUSTER_TESTING_PARSED", "42")
+
+	config, sources, err := LoadWithSources()
+	assert.NoError(err)
+	assert.Equal("default", config["testing"]["fromDefault"])
+	assert.Equal(SourceDefault, sources["testing"]["fromDefault"])
+	assert.Equal("file", config["testing"]["fromFile"])
+	assert.Equal(SourceFile, sources["testing"]["fromFile"])
+	// the environment takes precedence over the file
+	assert.Equal("env", config["testing"]["fromEnv"])
+	assert.Equal(SourceEnv, sources["testing"]["fromEnv"])
+	assert.Equal(42.0, config["testing"]["parsed"])
+	assert.Equal(SourceEnv, sources["testing"]["parsed"])
+
+	os.Setenv("TASKCLUSTER_TESTING_PARSED", "not json")
+	_, _, err = LoadWithSources()
+	assert.Error(err, "values to parse must be JSON")
+}
+
+func TestLoadWithSourcesInvalidFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "taskcluster-cli")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+	defer os.Setenv("XDG_CONFIG_HOME", os.Getenv("XDG_CONFIG_HOME"))
+	os.Setenv("XDG_CONFIG_HOME", dir)
+
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "taskcluster.yml"), []byte("testing: ["), 0600))
+	_, _, err = LoadWithSources()
+	assert.Error(err)
+}
@@ -0,0 +1,71 @@
+// Package scopes implements the local handling of taskcluster scopes, such as
+// matching and normalizing sets of scopes, so that commands agree on their
+// semantics without calling the auth service.
+//
+// A scope ending with a '*' satisfies every scope starting with what precedes
+// the '*', e.g. queue:* satisfies queue:create-task:foo, as well as queue:
+// and queue:* itself; any other scope only satisfies itself.
+package scopes
+
+import (
+	"sort"
+	"strings"
+)
+
+// Scope is a single taskcluster scope.
+type Scope string
+
+// Satisfies tells whether s satisfies other: s is other, or s ends with a '*'
+// and other starts with what precedes it.
+func (s Scope) Satisfies(other Scope) bool {
+	if s == other {
+		return true
+	}
+	return strings.HasSuffix(string(s), "*") && strings.HasPrefix(string(other), strings.TrimSuffix(string(s), "*"))
+}
+
+// SatisfyingSet tells whether granted satisfies required, that is whether
+// each scope of required is satisfied by a scope of granted. An empty
+// required is satisfied by any granted.
+func SatisfyingSet(granted, required []string) bool {
+outer:
+	for _, r := range required {
+		for _, g := range granted {
+			if Scope(g).Satisfies(Scope(r)) {
+				continue outer
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Normalize returns the minimal set of scopes equivalent to scopes: sorted,
+// without duplicates, and without the scopes satisfied by another scope of
+// the set ending with a '*'.
+func Normalize(scopes []string) []string {
+	stars := []Scope{}
+	for _, scope := range scopes {
+		if strings.HasSuffix(scope, "*") {
+			stars = append(stars, Scope(scope))
+		}
+	}
+
+	seen := make(map[string]bool, len(scopes))
+	result := []string{}
+outer:
+	for _, scope := range scopes {
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		for _, star := range stars {
+			if Scope(scope) != star && star.Satisfies(Scope(scope)) {
+				continue outer
+			}
+		}
+		result = append(result, scope)
+	}
+	sort.Strings(result)
+	return result
+}
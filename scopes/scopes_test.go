@@ -0,0 +1,81 @@
+package scopes
+
+import (
+	"testing"
+
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]string{}, Normalize(nil))
+	assert.Equal([]string{"a", "b"}, Normalize([]string{"b", "a", "b"}))
+	assert.Equal([]string{"queue:*"}, Normalize([]string{"queue:*", "queue:create-task:x"}))
+}
+
+func TestNormalizeWildcards(t *testing.T) {
+	assert := assert.New(t)
+
+	// a scope ending with '*' satisfies the bare prefix too
+	assert.Equal([]string{"a:*", "b:c"}, Normalize([]string{"a:b", "a:*", "b:c", "a:*", "a:"}))
+	// '*' satisfies everything
+	assert.Equal([]string{"*"}, Normalize([]string{"queue:*", "*", "assume:x"}))
+	// the longer wildcard is satisfied by the shorter one
+	assert.Equal([]string{"queue:*"}, Normalize([]string{"queue:create-task:*", "queue:*"}))
+	// a '*' in the middle of a scope is not a wildcard
+	assert.Equal([]string{"a*b", "a*bc"}, Normalize([]string{"a*b", "a*bc"}))
+	// scopes are not split on ':', so the prefix matches mid-segment
+	assert.Equal([]string{"queue:get-*"}, Normalize([]string{"queue:get-artifact:x", "queue:get-*"}))
+	// a scope without the wildcard's full prefix isn't satisfied by it
+	assert.Equal([]string{"queue", "queue:*"}, Normalize([]string{"queue", "queue:*"}))
+}
+
+func TestSatisfies(t *testing.T) {
+	for _, tc := range []struct {
+		scope, other string
+		satisfies    bool
+	}{
+		{"queue:create-task", "queue:create-task", true},
+		{"queue:create-task", "queue:create-task:x", false},
+		{"queue:create-task:x", "queue:create-task", false},
+		{"queue:*", "queue:create-task:x", true},
+		{"queue:*", "queue:", true},
+		{"queue:*", "queue:*", true},
+		{"queue:*", "queue", false},
+		{"queue:*", "index:x", false},
+		{"queue:create-task:*", "queue:*", false},
+		{"queue:get-*", "queue:get-artifact:x", true},
+		{"*", "queue:create-task:x", true},
+		{"*", "", true},
+		{"*", "*", true},
+		{"a*b", "a*b", true},
+		{"a*b", "axb", false},
+		{"", "", true},
+		{"", "queue:x", false},
+		{"queue:x", "*", false},
+	} {
+		assert.Equal(t, tc.satisfies, Scope(tc.scope).Satisfies(Scope(tc.other)), "%q satisfies %q", tc.scope, tc.other)
+	}
+}
+
+func TestSatisfyingSet(t *testing.T) {
+	for _, tc := range []struct {
+		granted, required []string
+		satisfying        bool
+	}{
+		{nil, nil, true},
+		{[]string{"queue:x"}, nil, true},
+		{nil, []string{"queue:x"}, false},
+		{[]string{"queue:x"}, []string{"queue:x"}, true},
+		{[]string{"queue:x"}, []string{"queue:x", "queue:y"}, false},
+		{[]string{"queue:x", "queue:y"}, []string{"queue:y", "queue:x"}, true},
+		{[]string{"queue:*"}, []string{"queue:x", "queue:y:z"}, true},
+		{[]string{"queue:*"}, []string{"queue:x", "index:y"}, false},
+		{[]string{"queue:*", "index:y"}, []string{"queue:x", "index:y"}, true},
+		{[]string{"*"}, []string{"queue:x", "index:y", "*"}, true},
+		{[]string{"queue:x:*"}, []string{"queue:*"}, false},
+	} {
+		assert.Equal(t, tc.satisfying, SatisfyingSet(tc.granted, tc.required), "%q satisfies %q", tc.granted, tc.required)
+	}
+}
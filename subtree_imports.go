@@ -5,8 +5,14 @@ package main
 
 import _ "github.com/taskcluster/taskcluster-cli/apis"
 import _ "github.com/taskcluster/taskcluster-cli/cmds/config"
+import _ "github.com/taskcluster/taskcluster-cli/cmds/decode-certificate"
+import _ "github.com/taskcluster/taskcluster-cli/cmds/expand-scope"
 import _ "github.com/taskcluster/taskcluster-cli/cmds/from-now"
 import _ "github.com/taskcluster/taskcluster-cli/cmds/group"
+import _ "github.com/taskcluster/taskcluster-cli/cmds/hook"
+import _ "github.com/taskcluster/taskcluster-cli/cmds/index"
+import _ "github.com/taskcluster/taskcluster-cli/cmds/scope"
+import _ "github.com/taskcluster/taskcluster-cli/cmds/secret"
 import _ "github.com/taskcluster/taskcluster-cli/cmds/signin"
 import _ "github.com/taskcluster/taskcluster-cli/cmds/slugid"
 import _ "github.com/taskcluster/taskcluster-cli/cmds/task"
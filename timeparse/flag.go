@@ -0,0 +1,40 @@
+package timeparse
+
+import (
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// durationValue is a pflag.Value for the durations given as time offsets,
+// such as `1d`, as well as those understood by time.ParseDuration.
+type durationValue time.Duration
+
+func (d *durationValue) Set(s string) error {
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		if v, err = ParseDuration(s); err != nil {
+			return err
+		}
+	}
+	*d = durationValue(v)
+	return nil
+}
+
+// String formats the duration the way time.ParseDuration parses it, so that
+// pflag.FlagSet.GetDuration reads the flag like any other duration flag.
+func (d *durationValue) String() string {
+	return time.Duration(*d).String()
+}
+
+func (d *durationValue) Type() string {
+	return "duration"
+}
+
+// AddDurationFlag registers a duration flag on flags, like
+// pflag.FlagSet.Duration, which also accepts time offsets such as `1d` or
+// `2 hours`. Its value is read with pflag.FlagSet.GetDuration.
+func AddDurationFlag(flags *pflag.FlagSet, name string, value time.Duration, usage string) {
+	d := durationValue(value)
+	flags.Var(&d, name, usage)
+}
@@ -0,0 +1,188 @@
+// Package timeparse parses the time expressions accepted by the commands,
+// such as `--since`, `--start` or `--expiry` flags, so that they all behave
+// the same way.
+//
+// A time expression is either an RFC3339 timestamp, or an offset relative to
+// the current time of the form `1 day 2 hours 3 minutes`, which can also be
+// written `1d2h3m`. An offset can be negative, as in `-2h`. The durations of
+// flags such as `--interval` or `--timeout` are offsets too, without years or
+// months, which have no fixed length.
+package timeparse
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Offset is a relative time, as parsed by ParseOffset.
+type Offset struct {
+	Negative bool
+	Years    int
+	Months   int
+	Weeks    int
+	Days     int
+	Hours    int
+	Minutes  int
+	Seconds  int
+}
+
+// Regexp taken from github.com/taskcluster/taskcluster-client/blob/master/lib/parsetime.js,
+// with `m` accepted for minutes
+var offsetRegexp = regexp.MustCompile(
+	// beginning and sign (group 2)
+	`^(\s*(-|\+))?` +
+		// years offset (group 4)
+		`(\s*(\d+)\s*y((ears?)|r)?)?` +
+		// months offset (group 8)
+		`(\s*(\d+)\s*mo(nths?)?)?` +
+		// weeks offset (group 11)
+		`(\s*(\d+)\s*w((eeks?)|k)?)?` +
+		// days offset (group 15)
+		`(\s*(\d+)\s*d(ays?)?)?` +
+		// hours offset (group 18)
+		`(\s*(\d+)\s*h((ours?)|r)?)?` +
+		// minutes offset (group 22)
+		`(\s*(\d+)\s*m(in(utes?)?)?)?` +
+		// seconds offset (group 26)
+		`(\s*(\d+)\s*s(ec(onds?)?)?)?` +
+		// the end
+		`\s*$`,
+)
+
+// ParseOffset takes a string of the form `1 day 2 hours 3 minutes` where
+// specification of each unit is optional. You can also use the short hand
+// `1d2h3m`, it's fairly tolerant of different spelling forms and whitespace.
+// A leading `-` makes the offset point to the past.
+func ParseOffset(str string) (Offset, error) {
+	offset := Offset{}
+
+	// an offset needs at least one unit, which the regexp alone allows to omit
+	str = strings.TrimSpace(str)
+	if !strings.ContainsAny(str, "0123456789") || !offsetRegexp.MatchString(str) {
+		return offset, fmt.Errorf("'%s' is not a valid time offset", str)
+	}
+
+	groupMatches := offsetRegexp.FindAllStringSubmatch(str, -1)
+
+	offset.Negative = groupMatches[0][2] == "-"
+	for _, unit := range []struct {
+		value *int
+		group int
+	}{
+		{&offset.Years, 4},
+		{&offset.Months, 8},
+		{&offset.Weeks, 11},
+		{&offset.Days, 15},
+		{&offset.Hours, 18},
+		{&offset.Minutes, 22},
+		{&offset.Seconds, 26},
+	} {
+		var err error
+		if *unit.value, err = atoiHelper(groupMatches[0][unit.group]); err != nil {
+			return Offset{}, fmt.Errorf("'%s' is not a valid time offset: %v", str, err)
+		}
+	}
+
+	return offset, nil
+}
+
+// fixed returns the part of the offset which has a fixed length, that is
+// everything but years and months, ignoring the sign. It fails if that
+// doesn't fit a time.Duration.
+func (o Offset) fixed() (time.Duration, error) {
+	// logic taken from github.com/taskcluster/taskcluster-client/blob/master/lib/utils.js
+	var total time.Duration
+	for _, term := range []struct {
+		count int
+		unit  time.Duration
+	}{
+		{o.Weeks, 7 * 24 * time.Hour},
+		{o.Days, 24 * time.Hour},
+		{o.Hours, time.Hour},
+		{o.Minutes, time.Minute},
+		{o.Seconds, time.Second},
+	} {
+		if int64(term.count) > (math.MaxInt64-int64(total))/int64(term.unit) {
+			return 0, errors.New("the offset is too large")
+		}
+		total += time.Duration(term.count) * term.unit
+	}
+	return total, nil
+}
+
+// From returns the time which is the offset away from t.
+func (o Offset) From(t time.Time) (time.Time, error) {
+	fixed, err := o.fixed()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if o.Negative {
+		return t.Add(-fixed).AddDate(-o.Years, -o.Months, 0), nil
+	}
+	return t.Add(fixed).AddDate(o.Years, o.Months, 0), nil
+}
+
+// Duration returns the offset as a time.Duration. That is not possible for
+// offsets in years or months, whose length varies.
+func (o Offset) Duration() (time.Duration, error) {
+	if o.Years != 0 || o.Months != 0 {
+		return 0, errors.New("offsets in years or months can't be used as a duration")
+	}
+	fixed, err := o.fixed()
+	if err != nil {
+		return 0, err
+	}
+	if o.Negative {
+		return -fixed, nil
+	}
+	return fixed, nil
+}
+
+// Parse returns the time described by str, which is either an RFC3339
+// timestamp or an offset relative to now.
+func Parse(str string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(str)); err == nil {
+		return t, nil
+	}
+	offset, err := ParseOffset(str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is neither an RFC3339 timestamp nor a valid time offset", str)
+	}
+	t, err := offset.From(now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("'%s' is not a valid time offset: %v", str, err)
+	}
+	return t, nil
+}
+
+// ParseDuration returns the duration described by the offset str.
+func ParseDuration(str string) (time.Duration, error) {
+	offset, err := ParseOffset(str)
+	if err != nil {
+		return 0, err
+	}
+	d, err := offset.Duration()
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a valid duration: %v", str, err)
+	}
+	return d, nil
+}
+
+// atoiHelper parses a number of units matched by offsetRegexp, which may be
+// missing. The regexp only matches digits, but they may be too many to fit an
+// int.
+func atoiHelper(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s is out of range", s)
+	}
+	return i, nil
+}
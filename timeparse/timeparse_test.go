@@ -0,0 +1,171 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	assert "github.com/stretchr/testify/require"
+)
+
+func TestParseOffsetComplete(t *testing.T) {
+	assert := assert.New(t)
+	offset, err := ParseOffset("1 year 2 months 3 weeks 4 days 5 hours 6 minutes 7 seconds")
+
+	assert.NoError(err, "the string should parse without error")
+	assert.Equal(Offset{Years: 1, Months: 2, Weeks: 3, Days: 4, Hours: 5, Minutes: 6, Seconds: 7}, offset)
+}
+
+func TestParseOffsetIncomplete(t *testing.T) {
+	assert := assert.New(t)
+
+	// Test if we omit some fields
+	offset, err := ParseOffset("2 years 5 days 6 minutes")
+
+	assert.NoError(err, "the string should parse without error")
+	assert.Equal(Offset{Years: 2, Days: 5, Minutes: 6}, offset)
+}
+
+func TestParseOffsetShortHand(t *testing.T) {
+	assert := assert.New(t)
+
+	offset, err := ParseOffset("1d2h30m")
+	assert.NoError(err)
+	assert.Equal(Offset{Days: 1, Hours: 2, Minutes: 30}, offset)
+
+	offset, err = ParseOffset("3mo")
+	assert.NoError(err)
+	assert.Equal(Offset{Months: 3}, offset)
+
+	offset, err = ParseOffset("-2h")
+	assert.NoError(err)
+	assert.Equal(Offset{Negative: true, Hours: 2}, offset)
+}
+
+func TestParseOffsetInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	// Test if it's a valid time expression.
+	_, err := ParseOffset("this should produce an error.")
+	assert.Error(err, "the string should produce an error")
+
+	for _, str := range []string{"", "-", "2 fortnights"} {
+		_, err = ParseOffset(str)
+		assert.Error(err, "'%s' should produce an error", str)
+	}
+}
+
+func TestOffsetFrom(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2018, 1, 31, 12, 0, 0, 0, time.UTC)
+	from, err := Offset{Months: 1, Hours: 2, Minutes: 30}.From(now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2018, 3, 3, 14, 30, 0, 0, time.UTC), from)
+	from, err = Offset{Negative: true, Years: 1, Days: 1}.From(now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2017, 1, 30, 12, 0, 0, 0, time.UTC), from)
+
+	_, err = Offset{Weeks: 999999999}.From(now)
+	assert.Error(err, "999999999 weeks don't fit a time.Duration")
+}
+
+func TestParse(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2018, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	parsed, err := Parse("2018-01-01T00:00:00Z", now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), parsed)
+
+	parsed, err = Parse("-2h", now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2018, 1, 31, 10, 0, 0, 0, time.UTC), parsed)
+
+	parsed, err = Parse("1d", now)
+	assert.NoError(err)
+	assert.Equal(time.Date(2018, 2, 1, 12, 0, 0, 0, time.UTC), parsed)
+
+	_, err = Parse("yesterday", now)
+	assert.Error(err)
+}
+
+func TestParseDuration(t *testing.T) {
+	assert := assert.New(t)
+
+	d, err := ParseDuration("30m")
+	assert.NoError(err)
+	assert.Equal(30*time.Minute, d)
+
+	d, err = ParseDuration("-1d")
+	assert.NoError(err)
+	assert.Equal(-24*time.Hour, d)
+
+	_, err = ParseDuration("1 year")
+	assert.Error(err, "years have no fixed length")
+}
+
+func TestAtoiHelper(t *testing.T) {
+	assert := assert.New(t)
+
+	i, err := atoiHelper("1")
+	assert.NoError(err)
+	assert.Equal(1, i)
+
+	i, err = atoiHelper("")
+	assert.NoError(err)
+	assert.Equal(0, i, "missing units count as 0")
+
+	_, err = atoiHelper("!")
+	assert.Error(err)
+	_, err = atoiHelper("99999999999999999999")
+	assert.Error(err)
+}
+
+func TestParseOffsetOverflow(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NotPanics(func() {
+		_, err := ParseOffset("99999999999999999999d")
+		assert.Error(err, "a number of units too large for an int should produce an error")
+		_, err = Parse("1h 99999999999999999999s", time.Now())
+		assert.Error(err)
+	})
+
+	_, err := Parse("999999999 weeks", time.Now())
+	assert.Error(err, "999999999 weeks don't fit a time.Duration")
+	_, err = ParseDuration("999999999 weeks")
+	assert.Error(err)
+	_, err = ParseDuration("2562047h 47m 16s")
+	assert.NoError(err, "the longest time.Duration is fine")
+	_, err = ParseDuration("2562047h 47m 17s")
+	assert.Error(err)
+}
+
+func TestAddDurationFlag(t *testing.T) {
+	assert := assert.New(t)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddDurationFlag(flags, "interval", time.Minute, "")
+	d, err := flags.GetDuration("interval")
+	assert.NoError(err)
+	assert.Equal(time.Minute, d)
+
+	for value, expected := range map[string]time.Duration{
+		"1d":       24 * time.Hour,
+		"2 hours":  2 * time.Hour,
+		"1m30s":    90 * time.Second,
+		"500ms":    500 * time.Millisecond,
+		"1h2m3.5s": time.Hour + 2*time.Minute + 3500*time.Millisecond,
+	} {
+		assert.NoError(flags.Set("interval", value))
+		d, err = flags.GetDuration("interval")
+		assert.NoError(err)
+		assert.Equal(expected, d, value)
+	}
+
+	assert.Error(flags.Set("interval", "1 year"))
+	assert.Error(flags.Set("interval", "999999999 weeks"))
+	assert.Error(flags.Set("interval", "soon"))
+}